@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/jackrudenko/pomodorocli/model"
+	"github.com/jackrudenko/pomodorocli/storage"
+)
+
+// Env is the environment a command runs in
+type Env struct {
+	// Where the command writes its output
+	Stdout io.Writer
+	// Where tasks, settings and the session history are stored
+	Storage storage.Options
+}
+
+// Command is a non-interactive subcommand such as "report"
+type Command struct {
+	// One-line description shown in the usage text
+	Summary string
+	// Run executes the command with the arguments following its name
+	Run func(args []string, env Env) error
+}
+
+// commands maps subcommand names to their implementation
+var commands = map[string]Command{
+	"export": {
+		Summary: "Export focus sessions as iCalendar (export ics) or Timewarrior data (export timew)",
+		Run:     runExport,
+	},
+	"import": {
+		Summary: "Import tasks from Taskwarrior (import taskwarrior) or TODO comments in code (import todos)",
+		Run:     runImport,
+	},
+	"report": {
+		Summary: "Print a timesheet of focus time as Markdown, CSV or JSON",
+		Run:     runReport,
+	},
+	"sync": {
+		Summary: "Annotate imported Taskwarrior tasks with pomodoro counts (sync taskwarrior)",
+		Run:     runSync,
+	},
+}
+
+// IsCommand reports whether the given program argument names a subcommand
+func IsCommand(name string) bool {
+	_, ok := commands[name]
+	return ok
+}
+
+// Run executes the subcommand named by the first argument
+func Run(args []string, env Env) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no command given")
+	}
+
+	command, ok := commands[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+	// Asking a command for its flags is not an error
+	if err := command.Run(args[1:], env); err != nil && !errors.Is(err, flag.ErrHelp) {
+		return err
+	}
+	return nil
+}
+
+// Usage returns the list of subcommands for the help text
+func Usage() string {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var builder strings.Builder
+	for _, name := range names {
+		builder.WriteString(fmt.Sprintf("  %-10s %s\n", name, commands[name].Summary))
+	}
+	return builder.String()
+}
+
+// appData holds the tasks, settings and session history loaded for a command
+type appData struct {
+	storageManager *storage.StorageManager
+	taskManager    *model.TaskManager
+	settings       *model.Settings
+	history        *model.History
+}
+
+// loadData loads tasks, settings and the session history from the storage selected by the options
+func loadData(options storage.Options) (*appData, error) {
+	data := &appData{
+		taskManager: model.NewTaskManager(),
+		history:     model.NewHistory(),
+	}
+	settings := model.DefaultSettings()
+	data.settings = &settings
+
+	storageManager, err := storage.OpenStorageManager(options, data.taskManager, data.settings, data.history)
+	if err != nil {
+		return nil, err
+	}
+	if err := storageManager.LoadAll(); err != nil {
+		return nil, err
+	}
+	data.storageManager = storageManager
+
+	return data, nil
+}
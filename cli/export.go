@@ -0,0 +1,200 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jackrudenko/pomodorocli/interop"
+	"github.com/jackrudenko/pomodorocli/model"
+)
+
+// runExport implements "pomodorocli export <format>"
+func runExport(args []string, env Env) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing export format: use ics or timew")
+	}
+
+	switch args[0] {
+	case "ics":
+		return runExportICS(args[1:], env)
+	case "timew":
+		return runExportTimewarrior(args[1:], env)
+	default:
+		return fmt.Errorf("unknown export format %q: use ics or timew", args[0])
+	}
+}
+
+// runExportICS exports focus sessions as an iCalendar file
+func runExportICS(args []string, env Env) error {
+	flags := flag.NewFlagSet("export ics", flag.ContinueOnError)
+	from := flags.String("from", "", "First day to export: YYYY-MM-DD, today or yesterday (default: all history)")
+	to := flags.String("to", "", "Last day to export: YYYY-MM-DD, today or yesterday (default: today)")
+	merge := flags.Bool("merge", false, "Merge consecutive pomodoros on the same task into one event")
+	mergeGap := flags.Duration("merge-gap", 10*time.Minute, "Longest pause between pomodoros that are merged")
+	output := flags.String("output", "", "File to write (default: standard output)")
+	flags.SetOutput(env.Stdout)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	start, end, err := exportDateRange(*from, *to, time.Now())
+	if err != nil {
+		return err
+	}
+
+	data, err := loadData(env.Storage)
+	if err != nil {
+		return err
+	}
+
+	blocks := model.FocusBlocks(data.history.SessionsBetween(start, end), *merge, *mergeGap)
+
+	if *output == "" {
+		return writeICS(env.Stdout, blocks)
+	}
+
+	file, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	if err := writeICS(file, blocks); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(env.Stdout, "Exported %d event(s) to %s\n", len(blocks), *output)
+	return nil
+}
+
+// timewarriorMonthLayout is the layout of the month Timewarrior names its data files after
+const timewarriorMonthLayout = "2006-01"
+
+// runExportTimewarrior exports focus sessions as Timewarrior data-file lines
+func runExportTimewarrior(args []string, env Env) error {
+	flags := flag.NewFlagSet("export timew", flag.ContinueOnError)
+	from := flags.String("from", "", "First day to export: YYYY-MM-DD, today or yesterday (default: all history)")
+	to := flags.String("to", "", "Last day to export: YYYY-MM-DD, today or yesterday (default: today)")
+	output := flags.String("output", "", "Data directory to append each month's data file in, e.g. ~/.timewarrior/data, or one month's data file such as 2026-10.data (default: standard output)")
+	flags.SetOutput(env.Stdout)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	start, end, err := exportDateRange(*from, *to, time.Now())
+	if err != nil {
+		return err
+	}
+
+	data, err := loadData(env.Storage)
+	if err != nil {
+		return err
+	}
+
+	sessions := data.history.SessionsBetween(start, end)
+	if *output == "" {
+		_, err := interop.WriteTimewarrior(env.Stdout, sessions, nil)
+		return err
+	}
+
+	// Timewarrior keeps the intervals started in each month (in UTC) in a file named after it
+	months := make([]string, 0)
+	byMonth := make(map[string][]model.Session)
+	for _, session := range sessions {
+		if !session.IsFocus() {
+			continue
+		}
+		month := session.Start.UTC().Format(timewarriorMonthLayout)
+		if _, ok := byMonth[month]; !ok {
+			months = append(months, month)
+		}
+		byMonth[month] = append(byMonth[month], session)
+	}
+
+	if info, err := os.Stat(*output); err == nil && info.IsDir() {
+		for _, month := range months {
+			if err := appendTimewarriorFile(filepath.Join(*output, month+".data"), byMonth[month], env); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// A single data file only takes the intervals of its own month
+	month := strings.TrimSuffix(filepath.Base(*output), ".data")
+	if _, err := time.Parse(timewarriorMonthLayout, month); err != nil {
+		return fmt.Errorf("%s is not a Timewarrior data file: name it YYYY-MM.data or give the data directory", *output)
+	}
+	return appendTimewarriorFile(*output, byMonth[month], env)
+}
+
+// appendTimewarriorFile appends focus sessions to a Timewarrior data file. Data files are
+// append-only logs, so an existing one is never truncated, and the intervals an earlier
+// export already added are skipped.
+func appendTimewarriorFile(path string, sessions []model.Session, env Env) error {
+	existing, err := readTimewarriorFile(path)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	written, err := interop.WriteTimewarrior(file, sessions, existing)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(env.Stdout, "Appended %d interval(s) to %s\n", written, path)
+	return nil
+}
+
+// readTimewarriorFile returns the intervals already in a Timewarrior data file, if it exists
+func readTimewarriorFile(path string) (map[string]bool, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return interop.ReadTimewarriorIntervals(file)
+}
+
+// exportDateRange returns the half-open range of sessions to export, covering all history by default
+func exportDateRange(from, to string, now time.Time) (time.Time, time.Time, error) {
+	var start, end time.Time
+	if from != "" {
+		date, err := model.ParseDate(from, now)
+		if err != nil {
+			return start, end, err
+		}
+		start = date
+	}
+
+	// The last day is inclusive, and nothing can be recorded after now
+	end = model.StartOfDay(now).AddDate(0, 0, 1)
+	if to != "" {
+		date, err := model.ParseDate(to, now)
+		if err != nil {
+			return start, end, err
+		}
+		end = date.AddDate(0, 0, 1)
+	}
+
+	if !start.Before(end) {
+		return start, end, fmt.Errorf("--from %s is after --to %s", start.Format(dateLayout), end.AddDate(0, 0, -1).Format(dateLayout))
+	}
+	return start, end, nil
+}
@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackrudenko/pomodorocli/model"
+	"github.com/jackrudenko/pomodorocli/storage"
+)
+
+func TestExportTimewarriorByMonth(t *testing.T) {
+	dataDir := t.TempDir()
+	options := storage.DataDirOptions(dataDir)
+	historyStorage, err := storage.NewJSONHistoryStorage(options.HistoryPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	september := time.Date(2026, 9, 30, 9, 0, 0, 0, time.UTC)
+	october := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	sessions := []model.Session{
+		{ID: "1", Mode: model.FocusMode, Start: september, End: september.Add(25 * time.Minute), Duration: 25 * time.Minute, Completed: true, TaskDescription: "Docs"},
+		{ID: "2", Mode: model.FocusMode, Start: october, End: october.Add(25 * time.Minute), Duration: 25 * time.Minute, Completed: true, TaskDescription: "Review"},
+	}
+	if err := historyStorage.SaveHistory(sessions); err != nil {
+		t.Fatal(err)
+	}
+
+	septemberLine := "inc 20260930T090000Z - 20260930T092500Z # Docs\n"
+	octoberLine := "inc 20261001T090000Z - 20261001T092500Z # Review\n"
+
+	tests := []struct {
+		name    string
+		output  string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name:   "data directory",
+			output: ".",
+			want:   map[string]string{"2026-09.data": septemberLine, "2026-10.data": octoberLine},
+		},
+		{
+			name:   "month file",
+			output: "2026-10.data",
+			want:   map[string]string{"2026-10.data": octoberLine},
+		},
+		{
+			name:    "other file",
+			output:  "sessions.txt",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timewDir := t.TempDir()
+			args := []string{"timew", "--from", "2026-09-01", "--to", "2026-10-31", "--output", filepath.Join(timewDir, tt.output)}
+
+			// Exporting twice adds every interval once
+			for i := 0; i < 2; i++ {
+				err := runExport(args, Env{Stdout: &bytes.Buffer{}, Storage: options})
+				if tt.wantErr {
+					if err == nil {
+						t.Fatal("export succeeded, want an error")
+					}
+					return
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			entries, err := os.ReadDir(timewDir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != len(tt.want) {
+				t.Errorf("got %d files, want %d", len(entries), len(tt.want))
+			}
+			for name, want := range tt.want {
+				content, err := os.ReadFile(filepath.Join(timewDir, name))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := string(content); got != want {
+					t.Errorf("%s = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestExportTimewarriorToStdout(t *testing.T) {
+	options := storage.DataDirOptions(t.TempDir())
+	var output bytes.Buffer
+	if err := runExport([]string{"timew"}, Env{Stdout: &output, Storage: options}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(output.String()) != "" {
+		t.Errorf("exported %q from an empty history", output.String())
+	}
+}
@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jackrudenko/pomodorocli/model"
+)
+
+const (
+	// Timestamp layout for UTC date-times in iCalendar files
+	icsTimeLayout = "20060102T150405Z"
+	// Maximum length of a content line in octets before it must be folded
+	icsMaxLineLength = 75
+)
+
+// writeICS writes focus blocks as an iCalendar file with one VEVENT per block
+func writeICS(w io.Writer, blocks []model.FocusBlock) error {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//pomodorocli//Focus Sessions//EN",
+		"CALSCALE:GREGORIAN",
+		"X-WR-CALNAME:Focus sessions",
+	}
+
+	for _, block := range blocks {
+		lines = append(lines, icsEvent(block)...)
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	// iCalendar requires CRLF line endings and folded long lines
+	var builder strings.Builder
+	for _, line := range lines {
+		builder.WriteString(foldICSLine(line))
+		builder.WriteString("\r\n")
+	}
+
+	_, err := io.WriteString(w, builder.String())
+	return err
+}
+
+// icsEvent returns the content lines of the VEVENT for a focus block
+func icsEvent(block model.FocusBlock) []string {
+	description := block.TaskDescription
+	if description == "" {
+		description = model.NoTaskName
+	}
+
+	details := []string{
+		fmt.Sprintf("Focus: %s", model.FormatDuration(block.Duration)),
+		"Pomodoros: " + model.FormatPomodoros(block.Pomodoros),
+	}
+	if block.Project != "" {
+		details = append(details, "Project: "+block.Project)
+	}
+	if len(block.Tags) > 0 {
+		details = append(details, "Tags: "+strings.Join(block.Tags, ", "))
+	}
+	if len(block.SessionIDs) > 1 {
+		details = append(details, fmt.Sprintf("Sessions: %d", len(block.SessionIDs)))
+	}
+
+	// Timestamps derive from the session itself so repeated exports are identical
+	lines := []string{
+		"BEGIN:VEVENT",
+		"UID:" + block.UID() + "@pomodorocli",
+		"DTSTAMP:" + block.End.UTC().Format(icsTimeLayout),
+		"DTSTART:" + block.Start.UTC().Format(icsTimeLayout),
+		"DTEND:" + block.End.UTC().Format(icsTimeLayout),
+		"SUMMARY:" + escapeICSText("🍅 "+description),
+		"DESCRIPTION:" + escapeICSText(strings.Join(details, "\n")),
+	}
+
+	categories := make([]string, 0, len(block.Tags)+1)
+	if block.Project != "" {
+		categories = append(categories, escapeICSText(block.Project))
+	}
+	for _, tag := range block.Tags {
+		categories = append(categories, escapeICSText(tag))
+	}
+	if len(categories) > 0 {
+		lines = append(lines, "CATEGORIES:"+strings.Join(categories, ","))
+	}
+
+	return append(lines, "TRANSP:OPAQUE", "END:VEVENT")
+}
+
+// escapeICSText escapes a TEXT property value
+func escapeICSText(text string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	)
+	return replacer.Replace(text)
+}
+
+// foldICSLine splits a content line into chunks of at most 75 octets, continuing
+// each chunk on a new line that starts with a space. Multi-byte characters are never split.
+func foldICSLine(line string) string {
+	if len(line) <= icsMaxLineLength {
+		return line
+	}
+
+	var builder strings.Builder
+	length := 0
+	limit := icsMaxLineLength
+	for _, r := range line {
+		size := len(string(r))
+		if length+size > limit {
+			builder.WriteString("\r\n ")
+			length = 0
+			// Continuation lines lose one octet to the leading space
+			limit = icsMaxLineLength - 1
+		}
+		builder.WriteRune(r)
+		length += size
+	}
+	return builder.String()
+}
@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackrudenko/pomodorocli/model"
+)
+
+func TestEscapeICSText(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Write docs", "Write docs"},
+		{"a, b; c", `a\, b\; c`},
+		{`back\slash`, `back\\slash`},
+		{"two\nlines", `two\nlines`},
+		{"two\r\nlines", `two\nlines`},
+	}
+
+	for _, tt := range tests {
+		if got := escapeICSText(tt.text); got != tt.want {
+			t.Errorf("escapeICSText(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestFoldICSLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+	}{
+		{"short", "SUMMARY:Write docs"},
+		{"exactly 75 octets", "SUMMARY:" + strings.Repeat("a", 67)},
+		{"long", "DESCRIPTION:" + strings.Repeat("abcdefghij", 20)},
+		{"multi-byte characters", "SUMMARY:" + strings.Repeat("🍅é", 40)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			folded := foldICSLine(tt.line)
+			chunks := strings.Split(folded, "\r\n")
+			for i, chunk := range chunks {
+				if len(chunk) > icsMaxLineLength {
+					t.Errorf("chunk %d is %d octets long", i, len(chunk))
+				}
+				if i > 0 {
+					if !strings.HasPrefix(chunk, " ") {
+						t.Errorf("chunk %d doesn't start with a space: %q", i, chunk)
+					}
+					chunks[i] = chunk[1:]
+				}
+			}
+			if unfolded := strings.Join(chunks, ""); unfolded != tt.line {
+				t.Errorf("unfolded %q, want %q", unfolded, tt.line)
+			}
+			if len(tt.line) <= icsMaxLineLength && folded != tt.line {
+				t.Errorf("folded a short line: %q", folded)
+			}
+		})
+	}
+}
+
+func TestWriteICS(t *testing.T) {
+	start := time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)
+	blocks := []model.FocusBlock{
+		{SessionIDs: []string{"s1"}, TaskDescription: "Write docs", Project: "acme", Tags: []string{"docs"},
+			Start: start, End: start.Add(25 * time.Minute), Duration: 25 * time.Minute, Pomodoros: 0.6},
+		{SessionIDs: []string{"s1", "s2"}, Part: 1, Start: start.Add(25 * time.Minute), End: start.Add(60 * time.Minute), Duration: 35 * time.Minute, Pomodoros: 1.4},
+	}
+
+	var output bytes.Buffer
+	if err := writeICS(&output, blocks); err != nil {
+		t.Fatal(err)
+	}
+	ics := output.String()
+
+	wantLines := []string{
+		"BEGIN:VCALENDAR",
+		"UID:s1@pomodorocli",
+		"DTSTART:20261015T090000Z",
+		"DTEND:20261015T092500Z",
+		"SUMMARY:🍅 Write docs",
+		`DESCRIPTION:Focus: 25m\nPomodoros: 0.6\nProject: acme\nTags: docs`,
+		"CATEGORIES:acme,docs",
+		"UID:s1-1@pomodorocli",
+		"SUMMARY:🍅 " + model.NoTaskName,
+		`DESCRIPTION:Focus: 35m\nPomodoros: 1.4\nSessions: 2`,
+		"END:VCALENDAR",
+	}
+	lines := strings.Split(strings.TrimSuffix(ics, "\r\n"), "\r\n")
+	for _, want := range wantLines {
+		found := false
+		for _, line := range lines {
+			if line == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("missing line %q in\n%s", want, ics)
+		}
+	}
+	if strings.Count(ics, "BEGIN:VEVENT") != len(blocks) {
+		t.Errorf("got %d events, want %d", strings.Count(ics, "BEGIN:VEVENT"), len(blocks))
+	}
+	if strings.Contains(strings.ReplaceAll(ics, "\r\n", ""), "\n") {
+		t.Error("lines don't all end with CRLF")
+	}
+}
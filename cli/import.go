@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jackrudenko/pomodorocli/interop"
+)
+
+// runImport implements "pomodorocli import <source>"
+func runImport(args []string, env Env) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing import source: use taskwarrior or todos")
+	}
+
+	switch args[0] {
+	case "taskwarrior":
+		return runImportTaskwarrior(args[1:], env)
+	case "todos":
+		return runImportTodos(args[1:], env)
+	default:
+		return fmt.Errorf("unknown import source %q: use taskwarrior or todos", args[0])
+	}
+}
+
+// runImportTaskwarrior imports the output of "task export"
+func runImportTaskwarrior(args []string, env Env) error {
+	flags := flag.NewFlagSet("import taskwarrior", flag.ContinueOnError)
+	file := flags.String("file", "", "File written by \"task export\" (default: standard input)")
+	flags.SetOutput(env.Stdout)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	input := io.Reader(os.Stdin)
+	if *file != "" {
+		f, err := os.Open(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		input = f
+	}
+
+	data, err := loadData(env.Storage)
+	if err != nil {
+		return err
+	}
+
+	result, err := interop.ImportTaskwarrior(data.taskManager, input)
+	if err != nil {
+		return err
+	}
+	if err := data.storageManager.SaveTasks(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(env.Stdout, "Imported Taskwarrior tasks: %s\n", result)
+	return nil
+}
+
+// runImportTodos imports the TODO and FIXME comments of a source tree
+func runImportTodos(args []string, env Env) error {
+	flags := flag.NewFlagSet("import todos", flag.ContinueOnError)
+	project := flags.String("project", "", "Project of new tasks (default: name of the directory)")
+	pomodoros := flags.Int("pomodoros", 1, "Planned pomodoros of new tasks")
+	flags.SetOutput(env.Stdout)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: import todos [options] <dir>")
+	}
+	if *pomodoros < 1 {
+		return fmt.Errorf("--pomodoros must be at least 1")
+	}
+
+	// Tasks remember the absolute directory so trees imported separately don't mix
+	root, err := filepath.Abs(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	if info, err := os.Stat(root); err != nil {
+		return err
+	} else if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", root)
+	}
+	if *project == "" {
+		*project = filepath.Base(root)
+	}
+
+	comments, err := interop.ScanTodoComments(root)
+	if err != nil {
+		return err
+	}
+
+	data, err := loadData(env.Storage)
+	if err != nil {
+		return err
+	}
+
+	options := interop.TodoImportOptions{Project: *project, Pomodoros: *pomodoros}
+	result := interop.ImportTodoComments(data.taskManager, root, comments, options, time.Now())
+	if err := data.storageManager.SaveTasks(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(env.Stdout, "Imported TODO comments from %s: %s\n", root, result)
+	return nil
+}
@@ -0,0 +1,233 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/jackrudenko/pomodorocli/model"
+)
+
+// Layout of the dates accepted and printed by the report command
+const dateLayout = "2006-01-02"
+
+// runReport implements "pomodorocli report"
+func runReport(args []string, env Env) error {
+	now := time.Now()
+
+	flags := flag.NewFlagSet("report", flag.ContinueOnError)
+	from := flags.String("from", "", "First day of the report: YYYY-MM-DD, today or yesterday (default: 6 days before --to)")
+	to := flags.String("to", "today", "Last day of the report: YYYY-MM-DD, today or yesterday")
+	groupBy := flags.String("group-by", "day", "Group focus time by day, task or project")
+	format := flags.String("format", "md", "Output format: md, csv or json")
+	flags.SetOutput(env.Stdout)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	toDate, err := model.ParseDate(*to, now)
+	if err != nil {
+		return err
+	}
+	fromDate := toDate.AddDate(0, 0, -6)
+	if *from != "" {
+		if fromDate, err = model.ParseDate(*from, now); err != nil {
+			return err
+		}
+	}
+	if fromDate.After(toDate) {
+		return fmt.Errorf("--from %s is after --to %s", fromDate.Format(dateLayout), toDate.Format(dateLayout))
+	}
+
+	grouping, err := model.ParseTimesheetGroupBy(*groupBy)
+	if err != nil {
+		return err
+	}
+
+	var render func(sheet model.Timesheet, w io.Writer) error
+	switch strings.ToLower(*format) {
+	case "md", "markdown":
+		render = renderMarkdownReport
+	case "csv":
+		render = renderCSVReport
+	case "json":
+		render = renderJSONReport
+	default:
+		return fmt.Errorf("invalid format %q: use md, csv or json", *format)
+	}
+
+	data, err := loadData(env.Storage)
+	if err != nil {
+		return err
+	}
+
+	sheet := model.BuildTimesheet(data.history, data.taskManager, fromDate, toDate, grouping)
+	return render(sheet, env.Stdout)
+}
+
+// reportColumns returns the column headers for a timesheet grouping
+func reportColumns(groupBy model.TimesheetGroupBy) []string {
+	switch groupBy {
+	case model.GroupByTask:
+		return []string{"Task", "Project", "Status", "Estimate", "Pomodoros", "Sessions", "Focus"}
+	case model.GroupByProject:
+		return []string{"Project", "Pomodoros", "Sessions", "Focus"}
+	default:
+		return []string{"Date", "Pomodoros", "Sessions", "Focus"}
+	}
+}
+
+// reportCells returns the cells of a row, formatting focus time with the given function
+func reportCells(row model.TimesheetRow, groupBy model.TimesheetGroupBy, focus func(time.Duration) string) []string {
+	counts := []string{
+		model.FormatPomodoros(row.Pomodoros),
+		fmt.Sprintf("%d", row.Sessions),
+		focus(row.FocusTime),
+	}
+
+	if groupBy != model.GroupByTask {
+		return append([]string{row.Label}, counts...)
+	}
+
+	estimate := ""
+	if row.Estimate > 0 {
+		estimate = fmt.Sprintf("%d", row.Estimate)
+	}
+	return append([]string{row.Label, row.Project, row.Status, estimate}, counts...)
+}
+
+// focusHours formats focus time as decimal hours for spreadsheets
+func focusHours(d time.Duration) string {
+	return fmt.Sprintf("%.2f", d.Hours())
+}
+
+// renderMarkdownReport writes the timesheet as a Markdown table
+func renderMarkdownReport(sheet model.Timesheet, w io.Writer) error {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("## Timesheet %s – %s\n\n", sheet.From.Format(dateLayout), sheet.To.Format(dateLayout)))
+
+	columns := reportColumns(sheet.GroupBy)
+	builder.WriteString("| " + strings.Join(columns, " | ") + " |\n")
+
+	// Right-align the numeric columns
+	alignments := make([]string, len(columns))
+	for i := range columns {
+		alignments[i] = "---"
+		if i >= len(columns)-3 {
+			alignments[i] = "--:"
+		}
+	}
+	builder.WriteString("| " + strings.Join(alignments, " | ") + " |\n")
+
+	for _, row := range sheet.Rows {
+		cells := reportCells(row, sheet.GroupBy, model.FormatDuration)
+		for i, cell := range cells {
+			cells[i] = escapeMarkdownCell(cell)
+		}
+		builder.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+
+	// Bold total row, with empty task detail columns
+	total := reportCells(sheet.Total, sheet.GroupBy, model.FormatDuration)
+	for i := range total {
+		if i > 0 && i < len(total)-3 {
+			total[i] = ""
+		} else {
+			total[i] = "**" + total[i] + "**"
+		}
+	}
+	builder.WriteString("| " + strings.Join(total, " | ") + " |\n")
+
+	_, err := io.WriteString(w, builder.String())
+	return err
+}
+
+// escapeMarkdownCell keeps pipes in task descriptions from breaking the table
+func escapeMarkdownCell(cell string) string {
+	return strings.ReplaceAll(cell, "|", "\\|")
+}
+
+// renderCSVReport writes the timesheet as CSV with focus time in decimal hours and a total row
+func renderCSVReport(sheet model.Timesheet, w io.Writer) error {
+	writer := csv.NewWriter(w)
+
+	columns := reportColumns(sheet.GroupBy)
+	columns[len(columns)-1] = "Focus Hours"
+	if err := writer.Write(columns); err != nil {
+		return err
+	}
+
+	for _, row := range sheet.Rows {
+		if err := writer.Write(reportCells(row, sheet.GroupBy, focusHours)); err != nil {
+			return err
+		}
+	}
+
+	total := reportCells(sheet.Total, sheet.GroupBy, focusHours)
+	for i := 1; i < len(total)-3; i++ {
+		total[i] = ""
+	}
+	if err := writer.Write(total); err != nil {
+		return err
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// jsonReport is the JSON representation of a timesheet
+type jsonReport struct {
+	From    string          `json:"from"`
+	To      string          `json:"to"`
+	GroupBy string          `json:"group_by"`
+	Rows    []jsonReportRow `json:"rows"`
+	Total   jsonReportRow   `json:"total"`
+}
+
+// jsonReportRow is the JSON representation of a timesheet row
+type jsonReportRow struct {
+	Key          string  `json:"key"`
+	Label        string  `json:"label"`
+	Project      string  `json:"project,omitempty"`
+	Status       string  `json:"status,omitempty"`
+	Estimate     int     `json:"estimate,omitempty"`
+	Pomodoros    float64 `json:"pomodoros"`
+	Sessions     int     `json:"sessions"`
+	FocusSeconds int64   `json:"focus_seconds"`
+}
+
+// newJSONReportRow converts a timesheet row to its JSON representation
+func newJSONReportRow(row model.TimesheetRow) jsonReportRow {
+	return jsonReportRow{
+		Key:          row.Key,
+		Label:        row.Label,
+		Project:      row.Project,
+		Status:       row.Status,
+		Estimate:     row.Estimate,
+		Pomodoros:    row.Pomodoros,
+		Sessions:     row.Sessions,
+		FocusSeconds: int64(row.FocusTime / time.Second),
+	}
+}
+
+// renderJSONReport writes the timesheet as indented JSON
+func renderJSONReport(sheet model.Timesheet, w io.Writer) error {
+	report := jsonReport{
+		From:    sheet.From.Format(dateLayout),
+		To:      sheet.To.Format(dateLayout),
+		GroupBy: string(sheet.GroupBy),
+		Rows:    make([]jsonReportRow, 0, len(sheet.Rows)),
+		Total:   newJSONReportRow(sheet.Total),
+	}
+	for _, row := range sheet.Rows {
+		report.Rows = append(report.Rows, newJSONReportRow(row))
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/jackrudenko/pomodorocli/interop"
+)
+
+// runSync implements "pomodorocli sync <target>"
+func runSync(args []string, env Env) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing sync target: use taskwarrior")
+	}
+
+	switch args[0] {
+	case "taskwarrior":
+		return runSyncTaskwarrior(args[1:], env)
+	default:
+		return fmt.Errorf("unknown sync target %q: use taskwarrior", args[0])
+	}
+}
+
+// runSyncTaskwarrior annotates imported Taskwarrior tasks with their pomodoro progress
+func runSyncTaskwarrior(args []string, env Env) error {
+	flags := flag.NewFlagSet("sync taskwarrior", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "Print the task commands instead of running them")
+	taskBinary := flags.String("task-bin", "task", "Taskwarrior executable")
+	flags.SetOutput(env.Stdout)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	data, err := loadData(env.Storage)
+	if err != nil {
+		return err
+	}
+
+	if *dryRun {
+		for _, change := range interop.PendingAnnotations(data.taskManager) {
+			for _, command := range change.Commands() {
+				fmt.Fprintf(env.Stdout, "%s %s\n", *taskBinary, shellQuote(command))
+			}
+		}
+		return nil
+	}
+
+	synced, syncErr := interop.SyncTaskwarrior(data.taskManager, *taskBinary)
+	// Record the annotations written so far, even if a later one failed
+	if synced > 0 {
+		if err := data.storageManager.SaveTasks(); err != nil {
+			return err
+		}
+	}
+	if syncErr != nil {
+		return syncErr
+	}
+
+	fmt.Fprintf(env.Stdout, "Updated %d Taskwarrior task annotation(s)\n", synced)
+	return nil
+}
+
+// shellQuote formats arguments for display, quoting those that contain spaces
+func shellQuote(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if strings.ContainsAny(arg, " \t'\"") {
+			arg = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+		}
+		quoted[i] = arg
+	}
+	return strings.Join(quoted, " ")
+}
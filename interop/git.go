@@ -0,0 +1,115 @@
+package interop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jackrudenko/pomodorocli/model"
+)
+
+const (
+	// gitBinary is the git executable, looked up in PATH
+	gitBinary = "git"
+	// gitTimeout is how long a git command may run before it is killed, e.g. on a slow network filesystem
+	gitTimeout = 10 * time.Second
+)
+
+// GitCheckout is the repository and branch checked out in a directory
+type GitCheckout struct {
+	// Top-level directory of the repository
+	Root   string
+	Branch string
+}
+
+// DetectGitCheckout returns the repository and branch checked out in a directory
+func DetectGitCheckout(dir string) (GitCheckout, error) {
+	var checkout GitCheckout
+
+	root, err := runGit(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return checkout, err
+	}
+	branch, err := runGit(dir, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return checkout, err
+	}
+	if branch == "HEAD" {
+		return checkout, errors.New("HEAD is detached, not on a branch")
+	}
+
+	checkout.Root = filepath.Clean(root)
+	checkout.Branch = branch
+	return checkout, nil
+}
+
+// BranchTaskDescription turns a branch name such as "feature/login-form" into a task description
+func BranchTaskDescription(branch string) string {
+	name := branch[strings.LastIndex(branch, "/")+1:]
+	name = strings.Join(strings.Fields(strings.NewReplacer("-", " ", "_", " ").Replace(name)), " ")
+	if name == "" {
+		return branch
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// GitCommits returns the commits on any local branch of a repository that the configured user
+// authored between from and to, oldest first
+func GitCommits(repo string, from, to time.Time) ([]model.Commit, error) {
+	args := []string{
+		"log", "--branches", "--reverse",
+		"--since=" + from.Format(time.RFC3339),
+		"--until=" + to.Format(time.RFC3339),
+		"--format=%H%x1f%aI%x1f%s",
+	}
+	// Only count the user's own commits when git knows who they are
+	if email, err := runGit(repo, "config", "user.email"); err == nil && email != "" {
+		args = append(args, "--author="+email)
+	}
+
+	output, err := runGit(repo, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	commits := make([]model.Commit, 0)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.SplitN(line, "\x1f", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		at, err := time.Parse(time.RFC3339, fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("git log: invalid date %q: %w", fields[1], err)
+		}
+		// --since and --until filter by commit date, but the session is about authoring
+		if at.Before(from.Truncate(time.Second)) || at.After(to) {
+			continue
+		}
+		commits = append(commits, model.Commit{Hash: fields[0], Subject: fields[2], At: at})
+	}
+	return commits, nil
+}
+
+// runGit runs a git command in a directory and returns its trimmed output
+func runGit(dir string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), gitTimeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, gitBinary, append([]string{"-C", dir}, args...)...).Output()
+	if ctx.Err() == context.DeadlineExceeded {
+		return "", fmt.Errorf("git %s: timed out after %s", args[0], gitTimeout)
+	}
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
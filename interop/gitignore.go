@@ -0,0 +1,157 @@
+package interop
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ignoreRule is a pattern read from a .gitignore file
+type ignoreRule struct {
+	// Directory of the .gitignore file relative to the scanned root ("" for the root)
+	base    string
+	pattern *regexp.Regexp
+	// Patterns starting with "!" re-include what earlier patterns excluded
+	negate bool
+	// Patterns ending with "/" only match directories
+	dirOnly bool
+	// Patterns containing a "/" match paths relative to base, others match names at any depth
+	anchored bool
+}
+
+// gitignore decides which paths of a tree git would ignore. It supports the common
+// .gitignore syntax: wildcards, "**", leading and trailing slashes and negation.
+type gitignore struct {
+	rules []ignoreRule
+}
+
+// load adds the patterns of the .gitignore file in dir, given relative to the root
+func (g *gitignore) load(root, dir string) error {
+	return g.loadFile(filepath.Join(root, filepath.FromSlash(dir), ".gitignore"), dir)
+}
+
+// loadFile adds the patterns of an ignore file that applies to the base directory
+func (g *gitignore) loadFile(file, base string) error {
+	f, err := os.Open(file)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if rule, ok := parseIgnoreRule(scanner.Text(), base); ok {
+			g.rules = append(g.rules, rule)
+		}
+	}
+	return scanner.Err()
+}
+
+// ignored reports whether a path relative to the root is ignored; the last matching pattern wins
+func (g *gitignore) ignored(rel string, isDir bool) bool {
+	ignored := false
+	for _, rule := range g.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+
+		sub := rel
+		if rule.base != "" {
+			if !strings.HasPrefix(rel, rule.base+"/") {
+				continue
+			}
+			sub = strings.TrimPrefix(rel, rule.base+"/")
+		}
+		if !rule.anchored {
+			sub = path.Base(sub)
+		}
+
+		if rule.pattern.MatchString(sub) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+// parseIgnoreRule parses a line of a .gitignore file, reporting false for blank lines and comments
+func parseIgnoreRule(line, base string) (ignoreRule, bool) {
+	rule := ignoreRule{base: base}
+
+	// Trailing spaces are ignored unless escaped
+	line = strings.TrimRight(line, " \t")
+	if strings.HasSuffix(line, "\\") {
+		line += " "
+	}
+	if line == "" || strings.HasPrefix(line, "#") {
+		return rule, false
+	}
+
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, "\\!") || strings.HasPrefix(line, "\\#") {
+		line = line[1:]
+	}
+
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimSuffix(line, "/")
+	}
+	rule.anchored = strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+	if line == "" {
+		return rule, false
+	}
+
+	pattern, err := regexp.Compile("^" + globToRegexp(line) + "$")
+	if err != nil {
+		return rule, false
+	}
+	rule.pattern = pattern
+	return rule, true
+}
+
+// globToRegexp converts a .gitignore glob into a regular expression
+func globToRegexp(glob string) string {
+	var builder strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			// Zero or more leading directories
+			builder.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			builder.WriteString(".*")
+			i++
+		case c == '*':
+			builder.WriteString("[^/]*")
+		case c == '?':
+			builder.WriteString("[^/]")
+		case c == '\\' && i+1 < len(glob):
+			i++
+			builder.WriteString(regexp.QuoteMeta(string(glob[i])))
+		case c == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				builder.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			builder.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		default:
+			builder.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return builder.String()
+}
@@ -0,0 +1,52 @@
+package interop
+
+import (
+	"testing"
+)
+
+func TestGitignoreIgnored(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []string
+		base  string
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"name at any depth", []string{"*.log"}, "", "a/b/debug.log", false, true},
+		{"other extension", []string{"*.log"}, "", "a/b/debug.txt", false, false},
+		{"star stops at slashes", []string{"docs/*.md"}, "", "docs/api/index.md", false, false},
+		{"anchored pattern", []string{"/build"}, "", "build", true, true},
+		{"anchored pattern below the root", []string{"/build"}, "", "src/build", true, false},
+		{"unanchored directory", []string{"build/"}, "", "src/build", true, true},
+		{"directory pattern skips files", []string{"build/"}, "", "build", false, false},
+		{"double star prefix", []string{"**/testdata"}, "", "a/b/testdata", true, true},
+		{"double star prefix at the root", []string{"**/testdata"}, "", "testdata", true, true},
+		{"double star suffix", []string{"vendor/**"}, "", "vendor/a/b.go", false, true},
+		{"double star middle", []string{"a/**/z.go"}, "", "a/b/c/z.go", false, true},
+		{"question mark", []string{"file?.go"}, "", "file1.go", false, true},
+		{"character class", []string{"file[0-9].go"}, "", "filex.go", false, false},
+		{"negated class", []string{"file[!0-9].go"}, "", "filex.go", false, true},
+		{"negation re-includes", []string{"*.log", "!keep.log"}, "", "keep.log", false, false},
+		{"last match wins", []string{"!keep.log", "*.log"}, "", "keep.log", false, true},
+		{"comments and blank lines", []string{"# *.go", "", "   "}, "", "main.go", false, false},
+		{"escaped hash", []string{`\#notes`}, "", "#notes", false, true},
+		{"nested file applies below its directory", []string{"*.tmp"}, "sub", "sub/x.tmp", false, true},
+		{"nested file doesn't apply elsewhere", []string{"*.tmp"}, "sub", "other/x.tmp", false, false},
+		{"nested anchored pattern", []string{"/out"}, "sub", "sub/out", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ignore gitignore
+			for _, line := range tt.rules {
+				if rule, ok := parseIgnoreRule(line, tt.base); ok {
+					ignore.rules = append(ignore.rules, rule)
+				}
+			}
+			if got := ignore.ignored(tt.path, tt.isDir); got != tt.want {
+				t.Errorf("ignored(%q) with %q = %v, want %v", tt.path, tt.rules, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package interop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// notifyTimeout is how long a notification command may run before it is killed, so a hung
+// command can't pile up processes on every reminder
+const notifyTimeout = 5 * time.Second
+
+// RunNotifyCommand runs a user-configured command such as "notify-send Pomodoro" with the
+// message appended as its last argument. The command is split on spaces, without shell quoting.
+func RunNotifyCommand(command, message string) error {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return errors.New("no notification command given")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, args[0], append(args[1:], message)...).CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("%s: timed out after %s", args[0], notifyTimeout)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w: %s", args[0], err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
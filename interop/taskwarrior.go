@@ -0,0 +1,199 @@
+package interop
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/jackrudenko/pomodorocli/model"
+)
+
+// Layout of the UTC timestamps used by Taskwarrior and Timewarrior
+const warriorTimeLayout = "20060102T150405Z"
+
+// Planned pomodoros for imported tasks when no similar task suggests an estimate
+const defaultImportPomodoros = 4
+
+// TaskwarriorTask is a task as printed by "task export"
+type TaskwarriorTask struct {
+	UUID        string   `json:"uuid"`
+	Description string   `json:"description"`
+	Status      string   `json:"status"`
+	Project     string   `json:"project,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Priority    string   `json:"priority,omitempty"`
+	Entry       string   `json:"entry,omitempty"`
+	Due         string   `json:"due,omitempty"`
+	End         string   `json:"end,omitempty"`
+}
+
+// ImportResult counts what an import did
+type ImportResult struct {
+	Added   int
+	Updated int
+	Skipped int
+	// Tasks completed because their source disappeared
+	Completed int
+}
+
+// String summarises the import for the user
+func (r ImportResult) String() string {
+	summary := fmt.Sprintf("%d added, %d updated, %d skipped", r.Added, r.Updated, r.Skipped)
+	if r.Completed > 0 {
+		summary += fmt.Sprintf(", %d completed", r.Completed)
+	}
+	return summary
+}
+
+// ImportTaskwarrior reads the JSON array printed by "task export" and adds the tasks to the
+// task manager. Tasks imported before are matched by UUID and updated in place, keeping their
+// pomodoro counts. Deleted tasks and recurrence templates are skipped.
+func ImportTaskwarrior(tm *model.TaskManager, r io.Reader) (ImportResult, error) {
+	var result ImportResult
+
+	var tasks []TaskwarriorTask
+	if err := json.NewDecoder(r).Decode(&tasks); err != nil {
+		return result, fmt.Errorf("reading Taskwarrior export: %w", err)
+	}
+
+	for _, twTask := range tasks {
+		if twTask.UUID == "" || twTask.Description == "" || twTask.Status == "deleted" || twTask.Status == "recurring" {
+			result.Skipped++
+			continue
+		}
+
+		task, found := tm.FindByTaskwarriorUUID(twTask.UUID)
+		if !found {
+			pomodoros := defaultImportPomodoros
+			if suggestion, ok := tm.SuggestEstimate(twTask.Description); ok {
+				pomodoros = suggestion.Pomodoros
+			}
+			task = model.NewTask(twTask.Description, pomodoros)
+			task.TaskwarriorUUID = twTask.UUID
+			if entry, err := parseWarriorTime(twTask.Entry); err == nil {
+				task.CreatedAt = entry
+			}
+		}
+
+		if err := applyTaskwarriorFields(&task, twTask); err != nil {
+			return result, err
+		}
+
+		if found {
+			tm.UpdateTask(task)
+			result.Updated++
+		} else {
+			tm.Tasks = append(tm.Tasks, task)
+			result.Added++
+		}
+	}
+
+	return result, nil
+}
+
+// applyTaskwarriorFields copies description, project, tags, priority, due date and status onto a task
+func applyTaskwarriorFields(task *model.Task, twTask TaskwarriorTask) error {
+	task.Description = twTask.Description
+	task.Project = twTask.Project
+	task.Tags = append([]string(nil), twTask.Tags...)
+	task.Priority = twTask.Priority
+
+	task.Due = nil
+	if twTask.Due != "" {
+		due, err := parseWarriorTime(twTask.Due)
+		if err != nil {
+			return fmt.Errorf("task %s: invalid due date: %w", twTask.UUID, err)
+		}
+		// Due dates are whole days in this app
+		day := model.StartOfDay(due.Local())
+		task.Due = &day
+	}
+
+	completed := twTask.Status == "completed"
+	if completed != task.Completed {
+		end, err := parseWarriorTime(twTask.End)
+		if err != nil {
+			end = time.Now()
+		}
+		task.SetCompleted(completed, end)
+	}
+	return nil
+}
+
+// parseWarriorTime parses a Taskwarrior or Timewarrior UTC timestamp
+func parseWarriorTime(value string) (time.Time, error) {
+	return time.Parse(warriorTimeLayout, value)
+}
+
+// TaskwarriorAnnotation returns the annotation describing a task's pomodoro progress
+func TaskwarriorAnnotation(task model.Task) string {
+	return fmt.Sprintf("pomodorocli: %s/%d pomodoros, %s focus",
+		model.FormatPomodoros(task.Pomodoros()), task.PlannedPomodoros, model.FormatDuration(task.TimeSpent))
+}
+
+// AnnotationSync is a change sync would make to a Taskwarrior task's annotations
+type AnnotationSync struct {
+	TaskID string
+	UUID   string
+	// Annotation to remove (empty if there is none yet) and annotation to add
+	Previous string
+	Current  string
+}
+
+// PendingAnnotations returns the imported tasks whose pomodoro annotation is out of date
+func PendingAnnotations(tm *model.TaskManager) []AnnotationSync {
+	pending := make([]AnnotationSync, 0)
+	for _, task := range tm.Tasks {
+		if task.TaskwarriorUUID == "" || task.InTrash() {
+			continue
+		}
+		annotation := TaskwarriorAnnotation(task)
+		if annotation == task.TaskwarriorAnnotation {
+			continue
+		}
+		pending = append(pending, AnnotationSync{
+			TaskID:   task.ID,
+			UUID:     task.TaskwarriorUUID,
+			Previous: task.TaskwarriorAnnotation,
+			Current:  annotation,
+		})
+	}
+	return pending
+}
+
+// Commands returns the Taskwarrior command lines that apply the change:
+// removing the previous annotation, if any, and adding the current one
+func (s AnnotationSync) Commands() [][]string {
+	commands := make([][]string, 0, 2)
+	if s.Previous != "" {
+		commands = append(commands, []string{"rc.confirmation=off", s.UUID, "denotate", s.Previous})
+	}
+	return append(commands, []string{"rc.confirmation=off", s.UUID, "annotate", s.Current})
+}
+
+// SyncTaskwarrior updates the annotations of imported Taskwarrior tasks with their pomodoro
+// progress by running the task binary, and records the annotation written on each task.
+// It stops at the first annotation that cannot be added; tasks synced before it keep their recorded annotation.
+func SyncTaskwarrior(tm *model.TaskManager, taskBinary string) (int, error) {
+	synced := 0
+	for _, change := range PendingAnnotations(tm) {
+		commands := change.Commands()
+		for i, args := range commands {
+			output, err := exec.Command(taskBinary, args...).CombinedOutput()
+			// The previous annotation may have been removed by hand, so only adding must succeed
+			if err != nil && i == len(commands)-1 {
+				return synced, fmt.Errorf("%s %s: %w: %s", taskBinary, strings.Join(args, " "), err, strings.TrimSpace(string(output)))
+			}
+		}
+
+		if task, found := tm.GetTask(change.TaskID); found {
+			task.TaskwarriorAnnotation = change.Current
+			tm.UpdateTask(task)
+		}
+		synced++
+	}
+	return synced, nil
+}
@@ -0,0 +1,86 @@
+package interop
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jackrudenko/pomodorocli/model"
+)
+
+// WriteTimewarrior writes focus sessions as Timewarrior data-file lines, one interval per session,
+// or per task of a session that switched tasks:
+//
+//	inc 20261015T090000Z - 20261015T092500Z # "Write docs" acme docs
+//
+// The tags are the task description, project and task tags. Breaks are not written, nor are
+// intervals in skip (as returned by ReadTimewarriorIntervals). It returns the number of
+// intervals written.
+func WriteTimewarrior(w io.Writer, sessions []model.Session, skip map[string]bool) (int, error) {
+	written := 0
+	for _, session := range sessions {
+		if !session.IsFocus() {
+			continue
+		}
+		for _, part := range session.Parts() {
+			if skip[timewarriorInterval(part)] {
+				continue
+			}
+			if _, err := fmt.Fprintln(w, TimewarriorLine(part)); err != nil {
+				return written, err
+			}
+			written++
+		}
+	}
+	return written, nil
+}
+
+// ReadTimewarriorIntervals returns the start and end of the closed intervals in a Timewarrior
+// data file, in the form used to skip them when writing
+func ReadTimewarriorIntervals(r io.Reader) (map[string]bool, error) {
+	intervals := make(map[string]bool)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 4 && fields[0] == "inc" && fields[2] == "-" {
+			intervals[fields[1]+" - "+fields[3]] = true
+		}
+	}
+	return intervals, scanner.Err()
+}
+
+// timewarriorInterval formats the start and end of a session as in a Timewarrior interval
+func timewarriorInterval(session model.Session) string {
+	return session.Start.UTC().Format(warriorTimeLayout) + " - " + session.End.UTC().Format(warriorTimeLayout)
+}
+
+// TimewarriorLine formats a session as a closed Timewarrior interval
+func TimewarriorLine(session model.Session) string {
+	line := "inc " + timewarriorInterval(session)
+
+	tags := make([]string, 0, len(session.Tags)+2)
+	if session.TaskDescription != "" {
+		tags = append(tags, session.TaskDescription)
+	}
+	if session.Project != "" {
+		tags = append(tags, session.Project)
+	}
+	tags = append(tags, session.Tags...)
+
+	if len(tags) == 0 {
+		return line
+	}
+	for i, tag := range tags {
+		tags[i] = quoteTimewarriorTag(tag)
+	}
+	return line + " # " + strings.Join(tags, " ")
+}
+
+// quoteTimewarriorTag quotes tags containing spaces or quotes, as Timewarrior does
+func quoteTimewarriorTag(tag string) string {
+	if !strings.ContainsAny(tag, " \t\"") {
+		return tag
+	}
+	return `"` + strings.ReplaceAll(tag, `"`, `\"`) + `"`
+}
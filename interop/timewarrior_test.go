@@ -0,0 +1,95 @@
+package interop
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackrudenko/pomodorocli/model"
+)
+
+func TestTimewarriorLine(t *testing.T) {
+	start := time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)
+	end := start.Add(25 * time.Minute)
+
+	tests := []struct {
+		name    string
+		session model.Session
+		want    string
+	}{
+		{"no tags", model.Session{Start: start, End: end}, "inc 20261015T090000Z - 20261015T092500Z"},
+		{"task, project and tags", model.Session{Start: start, End: end, TaskDescription: "Write docs", Project: "acme", Tags: []string{"docs"}},
+			`inc 20261015T090000Z - 20261015T092500Z # "Write docs" acme docs`},
+		{"quotes", model.Session{Start: start, End: end, TaskDescription: `Say "hi"`}, `inc 20261015T090000Z - 20261015T092500Z # "Say \"hi\""`},
+		{"local time", model.Session{Start: start.In(time.FixedZone("CEST", 2*60*60)), End: end}, "inc 20261015T090000Z - 20261015T092500Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TimewarriorLine(tt.session); got != tt.want {
+				t.Errorf("TimewarriorLine() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteTimewarriorSkipsExistingIntervals(t *testing.T) {
+	start := time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+	sessions := []model.Session{
+		{ID: "1", Mode: model.FocusMode, Start: at(0), End: at(25), Duration: 25 * time.Minute, Completed: true, TaskDescription: "Docs"},
+		{ID: "2", Mode: model.ShortBreakMode, Start: at(25), End: at(30), Duration: 5 * time.Minute, Completed: true},
+		{ID: "3", Mode: model.FocusMode, Start: at(30), End: at(55), Duration: 25 * time.Minute, Completed: true, Segments: []model.TaskSegment{
+			{TaskDescription: "Docs", Start: at(30), End: at(40), Duration: 10 * time.Minute, Pomodoros: 0.4},
+			{TaskDescription: "Review", Start: at(40), End: at(55), Duration: 15 * time.Minute, Pomodoros: 0.6},
+		}},
+	}
+
+	tests := []struct {
+		name     string
+		existing string
+		want     []string
+	}{
+		{
+			name: "empty file",
+			want: []string{
+				"inc 20261015T090000Z - 20261015T092500Z # Docs",
+				"inc 20261015T093000Z - 20261015T094000Z # Docs",
+				"inc 20261015T094000Z - 20261015T095500Z # Review",
+			},
+		},
+		{
+			name:     "earlier export",
+			existing: "inc 20261015T090000Z - 20261015T092500Z # Docs\ninc 20261015T093000Z - 20261015T094000Z # renamed\n",
+			want:     []string{"inc 20261015T094000Z - 20261015T095500Z # Review"},
+		},
+		{
+			name:     "other intervals",
+			existing: "inc 20261015T080000Z - 20261015T090000Z # meeting\ninc 20261015T090000Z\n",
+			want: []string{
+				"inc 20261015T090000Z - 20261015T092500Z # Docs",
+				"inc 20261015T093000Z - 20261015T094000Z # Docs",
+				"inc 20261015T094000Z - 20261015T095500Z # Review",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			skip, err := ReadTimewarriorIntervals(strings.NewReader(tt.existing))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var output bytes.Buffer
+			written, err := WriteTimewarrior(&output, sessions, skip)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := strings.Join(tt.want, "\n") + "\n"
+			if got := output.String(); got != want || written != len(tt.want) {
+				t.Errorf("wrote %d interval(s)\n%s\nwant\n%s", written, got, want)
+			}
+		})
+	}
+}
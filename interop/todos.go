@@ -0,0 +1,197 @@
+package interop
+
+import (
+	"bufio"
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackrudenko/pomodorocli/model"
+)
+
+// Files larger than this are skipped when scanning for TODO comments
+const maxTodoFileSize = 1 << 20
+
+// todoCommentPattern matches a TODO or FIXME after a comment marker, e.g. "// TODO(ana): retry",
+// capturing the keyword and the text
+var todoCommentPattern = regexp.MustCompile(`(?://|#|/\*|\*|--|;|<!--)\s*(TODO|FIXME)(?:\([^)]*\))?:\s*(.*)$`)
+
+// TodoComment is a TODO or FIXME comment found in a source file
+type TodoComment struct {
+	// Path relative to the scanned directory, with forward slashes
+	File string
+	Line int
+	// TODO or FIXME
+	Kind string
+	// Text following the keyword
+	Text string
+}
+
+// Key returns the comment as written, which identifies it across re-imports while lines move
+func (c TodoComment) Key() string {
+	return c.Kind + ": " + c.Text
+}
+
+// TodoImportOptions configures the tasks created from TODO comments
+type TodoImportOptions struct {
+	// Project of new tasks (empty for none)
+	Project string
+	// Planned pomodoros of new tasks
+	Pomodoros int
+}
+
+// ScanTodoComments walks a directory and returns the TODO and FIXME comments of its text files,
+// skipping the .git directory and the files excluded by .gitignore files
+func ScanTodoComments(root string) ([]TodoComment, error) {
+	comments := make([]TodoComment, 0)
+
+	ignore := &gitignore{}
+	if err := ignore.loadFile(filepath.Join(root, ".git", "info", "exclude"), ""); err != nil {
+		return nil, err
+	}
+
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if entry.IsDir() {
+			if rel == "." {
+				return ignore.load(root, "")
+			}
+			if entry.Name() == ".git" || ignore.ignored(rel, true) {
+				return filepath.SkipDir
+			}
+			return ignore.load(root, rel)
+		}
+
+		if !entry.Type().IsRegular() || ignore.ignored(rel, false) {
+			return nil
+		}
+		found, err := scanTodoFile(path, rel)
+		if err != nil {
+			return err
+		}
+		comments = append(comments, found...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+// scanTodoFile returns the TODO comments of a file, or none if it is large or binary
+func scanTodoFile(path, rel string) ([]TodoComment, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > maxTodoFileSize {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	// Treat files with NUL bytes near the start as binary, like git does
+	head := content
+	if len(head) > 8000 {
+		head = head[:8000]
+	}
+	if bytes.IndexByte(head, 0) >= 0 {
+		return nil, nil
+	}
+
+	comments := make([]TodoComment, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxTodoFileSize)
+	for line := 1; scanner.Scan(); line++ {
+		match := todoCommentPattern.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+		// Drop the end of block comments
+		text := strings.TrimSpace(match[2])
+		text = strings.TrimSpace(strings.TrimSuffix(strings.TrimSuffix(text, "*/"), "-->"))
+		comments = append(comments, TodoComment{File: rel, Line: line, Kind: match[1], Text: text})
+	}
+	return comments, scanner.Err()
+}
+
+// ImportTodoComments creates tasks for the TODO comments found in a directory. Comments
+// imported before are matched by file and text, so they are found again after lines move;
+// tasks whose comment has disappeared are completed. Comments without text are skipped.
+func ImportTodoComments(tm *model.TaskManager, root string, comments []TodoComment, options TodoImportOptions, now time.Time) ImportResult {
+	var result ImportResult
+
+	// Tasks imported from this directory before, by file and comment, in line order
+	existing := make(map[string][]int)
+	for i, task := range tm.Tasks {
+		if task.CodeComment != nil && task.CodeComment.Root == root {
+			key := task.CodeComment.File + "\x00" + task.CodeComment.Text
+			existing[key] = append(existing[key], i)
+		}
+	}
+	for _, indexes := range existing {
+		sort.SliceStable(indexes, func(a, b int) bool {
+			return tm.Tasks[indexes[a]].CodeComment.Line < tm.Tasks[indexes[b]].CodeComment.Line
+		})
+	}
+
+	for _, comment := range comments {
+		if comment.Text == "" {
+			result.Skipped++
+			continue
+		}
+
+		key := comment.File + "\x00" + comment.Key()
+		if indexes := existing[key]; len(indexes) > 0 {
+			existing[key] = indexes[1:]
+			task := &tm.Tasks[indexes[0]]
+			if task.CodeComment.Line != comment.Line {
+				task.CodeComment.Line = comment.Line
+				result.Updated++
+			}
+			continue
+		}
+
+		task := model.NewTask(comment.Text, options.Pomodoros)
+		task.CreatedAt = now
+		task.Project = options.Project
+		task.Tags = []string{strings.ToLower(comment.Kind)}
+		task.CodeComment = &model.CodeComment{
+			Root: root,
+			File: comment.File,
+			Line: comment.Line,
+			Text: comment.Key(),
+		}
+		tm.Tasks = append(tm.Tasks, task)
+		result.Added++
+	}
+
+	// The remaining tasks lost their comment, so the work is done
+	for _, indexes := range existing {
+		for _, i := range indexes {
+			task := &tm.Tasks[i]
+			if !task.Completed && !task.InTrash() {
+				task.SetCompleted(true, now)
+				result.Completed++
+			}
+		}
+	}
+
+	return result
+}
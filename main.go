@@ -6,6 +6,8 @@ import (
 	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jackrudenko/pomodorocli/cli"
+	"github.com/jackrudenko/pomodorocli/storage"
 	"github.com/jackrudenko/pomodorocli/ui"
 )
 
@@ -14,23 +16,71 @@ func main() {
 	timerOnly := flag.Bool("timer", false, "Show only the timer component")
 	tasksOnly := flag.Bool("tasks", false, "Show only the task list component")
 	printMode := flag.Bool("print", false, "Print the view and exit (debug mode)")
+	statusMode := flag.Bool("status", false, "Print today's goal progress and streak and exit")
+	todoTxt := flag.String("todotxt", "", "Store tasks in this todo.txt file instead of data/tasks.json")
+	markdown := flag.String("markdown", "", "Store tasks as the checklist items of this Markdown file, e.g. TODO.md")
+	branchTask := flag.Bool("branch-task", false, "Select the task linked to the current git branch, creating one if needed")
+	dataDir := flag.String("data", "", "Directory for the global tasks, settings and history (default: "+storage.DefaultDataDir()+")")
+	globalOnly := flag.Bool("global", false, "Use the global task list even inside a workspace")
 	showHelp := flag.Bool("help", false, "Show help information")
 
 	// Parse command-line flags
 	flag.Parse()
 
+	// Select where data is stored
+	defaultDataDir := *dataDir == ""
+	if defaultDataDir {
+		*dataDir = storage.DefaultDataDir()
+	}
+	options := storage.DataDirOptions(*dataDir)
+	options.TodoTxtPath = *todoTxt
+	options.MarkdownPath = *markdown
+
+	// Use the workspace of the working directory or one of its parents, if there is one
+	if !*globalOnly {
+		if dir, err := os.Getwd(); err == nil {
+			workspace, err := storage.FindWorkspace(dir)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Error:", err)
+				os.Exit(1)
+			}
+			options.Workspace = workspace
+		}
+	}
+
+	// Run a subcommand such as "report" instead of the interactive UI
+	if flag.NArg() > 0 && cli.IsCommand(flag.Arg(0)) {
+		if err := cli.Run(flag.Args(), cli.Env{Stdout: os.Stdout, Storage: options}); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	// Show help text if requested
 	if *showHelp {
 		fmt.Println("Pomodoro CLI - A terminal-based Pomodoro timer")
 		fmt.Println("\nUsage:")
 		fmt.Println("  pomodorocli [options]")
+		fmt.Println("  pomodorocli [options] <command> [command options]")
 		fmt.Println("\nOptions:")
 		flag.PrintDefaults()
+		fmt.Println("\nCommands:")
+		fmt.Print(cli.Usage())
 		os.Exit(0)
 	}
 
+	// Move on from the data directory that earlier versions kept where they were run
+	if defaultDataDir {
+		if legacyDir, err := storage.MigrateLegacyData(*dataDir); err != nil {
+			fmt.Fprintln(os.Stderr, "Error copying data from earlier versions:", err)
+		} else if legacyDir != "" {
+			fmt.Fprintf(os.Stderr, "Copied tasks and history from %s to %s\n", legacyDir, *dataDir)
+		}
+	}
+
 	// Create a new application
-	app := ui.NewApp()
+	app := ui.NewApp(options)
 
 	// Set timer-only mode if requested via command-line flag
 	if *timerOnly {
@@ -40,6 +90,21 @@ func main() {
 		app.SetTaskListOnlyMode(true)
 	}
 
+	// Select the task for the git branch checked out in the working directory
+	if *branchTask {
+		if dir, err := os.Getwd(); err == nil {
+			if err := app.SelectBranchTask(dir); err != nil {
+				fmt.Fprintln(os.Stderr, "Error selecting the branch task:", err)
+			}
+		}
+	}
+
+	// Handle status mode - print a one-line summary for shell prompts and status bars
+	if *statusMode {
+		fmt.Println(app.StatusLine())
+		os.Exit(0)
+	}
+
 	// Handle print mode - render the view and exit without running the interactive program
 	if *printMode {
 		fmt.Println(app.View())
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"math/rand"
+	"time"
+)
+
+// DefaultBreakActivities returns the activities suggested for breaks before any are configured
+func DefaultBreakActivities() []string {
+	return []string{
+		"Stand up and stretch",
+		"Take a short walk",
+		"Breathe slowly and deeply",
+		"Get a glass of water",
+		"Look out of a window",
+		"Roll your shoulders and neck",
+	}
+}
+
+// PickBreakActivity picks one of the activities at random, avoiding the previous one so that
+// consecutive breaks differ. It returns "" if there are no activities.
+func PickBreakActivity(activities []string, previous string) string {
+	candidates := make([]string, 0, len(activities))
+	for _, activity := range activities {
+		if activity != previous {
+			candidates = append(candidates, activity)
+		}
+	}
+	// A single activity has to be repeated
+	if len(candidates) == 0 {
+		candidates = activities
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+
+	random := rand.New(rand.NewSource(time.Now().UnixNano()))
+	return candidates[random.Intn(len(candidates))]
+}
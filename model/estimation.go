@@ -0,0 +1,236 @@
+package model
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// Maximum number of similar tasks a suggested estimate is based on
+	maxSimilarTasks = 5
+	// Minimum similarity score for a past task to count as similar
+	minSimilarity = 2
+)
+
+// EstimateAccuracy summarises how the estimates of a group of completed tasks compare to the pomodoros spent
+type EstimateAccuracy struct {
+	// Project name, or the first day of the week for trends
+	Name string
+	// First day of the week for trends (zero for projects)
+	Start time.Time
+	// Number of completed tasks in the group
+	Tasks int
+	// Sum of the initial estimates
+	Estimated int
+	// Sum of the pomodoros actually completed, including shares of split pomodoros
+	Actual float64
+	// Sum of the absolute differences between estimate and actual
+	AbsoluteError float64
+	// Tasks that took more or fewer pomodoros than estimated
+	Overruns  int
+	Underruns int
+	// Tasks whose plan was changed after creation
+	Reestimated int
+}
+
+// add adds a completed task's estimate and actual pomodoros to the group
+func (a *EstimateAccuracy) add(task Task) {
+	estimated := float64(task.InitialEstimate())
+	actual := task.Pomodoros()
+
+	a.Tasks++
+	a.Estimated += task.InitialEstimate()
+	a.Actual += actual
+	switch {
+	case actual > estimated:
+		a.Overruns++
+		a.AbsoluteError += actual - estimated
+	case actual < estimated:
+		a.Underruns++
+		a.AbsoluteError += estimated - actual
+	}
+	if len(task.Reestimates) > 0 {
+		a.Reestimated++
+	}
+}
+
+// Bias returns the relative error of the group's estimates: positive when tasks overran
+// (e.g. 0.25 means 25% more pomodoros than estimated), negative when they were overestimated
+func (a EstimateAccuracy) Bias() float64 {
+	if a.Estimated == 0 {
+		return 0
+	}
+	return (a.Actual - float64(a.Estimated)) / float64(a.Estimated)
+}
+
+// MeanAbsoluteError returns the average number of pomodoros by which an estimate was off
+func (a EstimateAccuracy) MeanAbsoluteError() float64 {
+	if a.Tasks == 0 {
+		return 0
+	}
+	return a.AbsoluteError / float64(a.Tasks)
+}
+
+// EstimationReport breaks estimate accuracy down by project and by week
+type EstimationReport struct {
+	Overall   EstimateAccuracy
+	ByProject []EstimateAccuracy
+	// One entry per week, oldest first; weeks end on the day of the report
+	ByWeek []EstimateAccuracy
+}
+
+// EstimationReport computes estimate accuracy for the completed tasks that had pomodoros spent on
+// them and are not in the trash.
+// The weekly trend covers the given number of weeks ending on the day of now.
+func (tm *TaskManager) EstimationReport(weeks int, now time.Time) EstimationReport {
+	report := EstimationReport{Overall: EstimateAccuracy{Name: "All tasks"}}
+
+	// Weekly buckets ending today, like the statistics view
+	end := StartOfDay(now).AddDate(0, 0, 1)
+	report.ByWeek = make([]EstimateAccuracy, weeks)
+	for i := range report.ByWeek {
+		start := end.AddDate(0, 0, -7*(weeks-i))
+		report.ByWeek[i] = EstimateAccuracy{Name: start.Format("Jan 2"), Start: start}
+	}
+
+	byProject := make(map[string]*EstimateAccuracy)
+	for _, task := range tm.Tasks {
+		if !isEstimated(task) {
+			continue
+		}
+		report.Overall.add(task)
+
+		project := tm.EffectiveProject(task.ID)
+		if project == "" {
+			project = NoProjectName
+		}
+		entry, ok := byProject[project]
+		if !ok {
+			entry = &EstimateAccuracy{Name: project}
+			byProject[project] = entry
+		}
+		entry.add(task)
+
+		// Tasks completed before completion times were recorded only count overall
+		if task.CompletedAt != nil && task.CompletedAt.Before(end) {
+			age := dayIndex(StartOfDay(*task.CompletedAt), end.AddDate(0, 0, -1))
+			if week := weeks - 1 - age/7; week >= 0 && week < weeks {
+				report.ByWeek[week].add(task)
+			}
+		}
+	}
+
+	for _, entry := range byProject {
+		report.ByProject = append(report.ByProject, *entry)
+	}
+	sort.Slice(report.ByProject, func(i, j int) bool {
+		if report.ByProject[i].Tasks != report.ByProject[j].Tasks {
+			return report.ByProject[i].Tasks > report.ByProject[j].Tasks
+		}
+		return report.ByProject[i].Name < report.ByProject[j].Name
+	})
+
+	return report
+}
+
+// isEstimated reports whether a task counts towards estimate accuracy. Tasks ticked off without
+// ever running the timer say nothing about effort.
+func isEstimated(task Task) bool {
+	return task.Completed && !task.InTrash() && task.InitialEstimate() > 0 && task.Pomodoros() > 0
+}
+
+// Suggestion is an estimate for a new task derived from similar completed tasks
+type Suggestion struct {
+	Pomodoros int
+	// Number of similar tasks the suggestion is based on
+	Basis int
+}
+
+// SuggestEstimate suggests the planned pomodoros for a new task from the pomodoros
+// actually spent on the most similar completed tasks. The input is parsed like new
+// task input, so project and tags count towards similarity. It reports false if no
+// sufficiently similar task exists.
+func (tm *TaskManager) SuggestEstimate(input string) (Suggestion, bool) {
+	description, attrs := ParseTaskInput(input)
+	words := significantWords(description)
+
+	type candidate struct {
+		task  Task
+		score int
+	}
+	candidates := make([]candidate, 0)
+
+	for _, task := range tm.Tasks {
+		if !isEstimated(task) {
+			continue
+		}
+
+		// Shared words weigh more than a shared project or tag
+		score := 0
+		for word := range significantWords(task.Description) {
+			if words[word] {
+				score += 2
+			}
+		}
+		if attrs.Project != "" && strings.EqualFold(attrs.Project, tm.EffectiveProject(task.ID)) {
+			score++
+		}
+		for _, tag := range attrs.Tags {
+			if task.HasTag(tag) {
+				score++
+			}
+		}
+
+		if score >= minSimilarity {
+			candidates = append(candidates, candidate{task: task, score: score})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return Suggestion{}, false
+	}
+
+	// Most similar first, then most recently created
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score > candidates[j].score
+		}
+		return candidates[i].task.CreatedAt.After(candidates[j].task.CreatedAt)
+	})
+	if len(candidates) > maxSimilarTasks {
+		candidates = candidates[:maxSimilarTasks]
+	}
+
+	// The median is robust against the odd task that ran far over
+	actuals := make([]float64, len(candidates))
+	for i, c := range candidates {
+		actuals[i] = c.task.Pomodoros()
+	}
+	sort.Float64s(actuals)
+	median := actuals[len(actuals)/2]
+	if len(actuals)%2 == 0 {
+		median = (actuals[len(actuals)/2-1] + actuals[len(actuals)/2]) / 2
+	}
+
+	pomodoros := int(math.Round(median))
+	if pomodoros < 1 {
+		pomodoros = 1
+	}
+	return Suggestion{Pomodoros: pomodoros, Basis: len(candidates)}, true
+}
+
+// significantWords returns the lower-cased words of a description that carry meaning
+func significantWords(description string) map[string]bool {
+	words := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(strings.ToLower(description), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r > 127)
+	}) {
+		// Skip short words such as "a", "to" and "the"
+		if len([]rune(word)) >= 4 {
+			words[word] = true
+		}
+	}
+	return words
+}
@@ -0,0 +1,185 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// TaskFilter is a parsed filter expression such as "project:acme !done due<=today review".
+//
+// Supported terms:
+//   - project:NAME     task belongs to the project
+//   - tag:NAME, +NAME  task has the tag
+//   - done             task is completed
+//   - due<=DATE        task is due on or before DATE (also <, =, >=, > and due:DATE)
+//   - any other word   fuzzy-matches the description, project, tags or notes
+//
+// Any term can be negated with a leading "!". All terms must match.
+type TaskFilter struct {
+	Expression string
+	terms      []filterTerm
+}
+
+// filterTerm is a single, possibly negated, condition of a filter
+type filterTerm struct {
+	negated bool
+	match   func(task Task) bool
+}
+
+// ParseFilter parses a filter expression relative to the given time
+func ParseFilter(expression string, now time.Time) (TaskFilter, error) {
+	filter := TaskFilter{Expression: strings.TrimSpace(expression)}
+
+	for _, word := range strings.Fields(expression) {
+		term := filterTerm{}
+		if strings.HasPrefix(word, "!") && len(word) > 1 {
+			term.negated = true
+			word = word[1:]
+		}
+
+		match, err := parseFilterWord(word, now)
+		if err != nil {
+			return TaskFilter{}, err
+		}
+		term.match = match
+		filter.terms = append(filter.terms, term)
+	}
+
+	return filter, nil
+}
+
+// parseFilterWord returns the predicate for a single filter word
+func parseFilterWord(word string, now time.Time) (func(task Task) bool, error) {
+	lower := strings.ToLower(word)
+
+	switch {
+	case lower == "done":
+		return func(task Task) bool { return task.Completed }, nil
+
+	case strings.HasPrefix(lower, "project:"):
+		project := word[len("project:"):]
+		return func(task Task) bool { return strings.EqualFold(task.Project, project) }, nil
+
+	case strings.HasPrefix(lower, "tag:"):
+		tag := word[len("tag:"):]
+		return func(task Task) bool { return task.HasTag(tag) }, nil
+
+	case strings.HasPrefix(word, "+") && len(word) > 1:
+		tag := word[1:]
+		return func(task Task) bool { return task.HasTag(tag) }, nil
+
+	case strings.HasPrefix(lower, "due"):
+		if match, ok, err := parseDueTerm(lower[len("due"):], now); ok {
+			return match, err
+		}
+	}
+
+	// Anything else is a fuzzy text search
+	return func(task Task) bool { return FuzzyMatchTask(word, task) }, nil
+}
+
+// parseDueTerm parses the comparison following "due", e.g. "<=today".
+// It reports ok=false if the text is not a due comparison at all.
+func parseDueTerm(rest string, now time.Time) (func(task Task) bool, bool, error) {
+	for _, op := range []string{"<=", ">=", "<", ">", "=", ":"} {
+		if !strings.HasPrefix(rest, op) {
+			continue
+		}
+
+		date, err := ParseDate(rest[len(op):], now)
+		if err != nil {
+			return nil, true, err
+		}
+
+		return func(task Task) bool {
+			if task.Due == nil {
+				return false
+			}
+			due := *task.Due
+			switch op {
+			case "<=":
+				return !due.After(date)
+			case ">=":
+				return !due.Before(date)
+			case "<":
+				return due.Before(date)
+			case ">":
+				return due.After(date)
+			default:
+				return due.Equal(date)
+			}
+		}, true, nil
+	}
+
+	return nil, false, nil
+}
+
+// Empty reports whether the filter has no terms and therefore matches every task
+func (f TaskFilter) Empty() bool {
+	return len(f.terms) == 0
+}
+
+// Match reports whether a task satisfies every term of the filter
+func (f TaskFilter) Match(task Task) bool {
+	for _, term := range f.terms {
+		if term.match(task) == term.negated {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the filter expression
+func (f TaskFilter) String() string {
+	return f.Expression
+}
+
+// FuzzyMatchTask reports whether the pattern fuzzy-matches any of the task's
+// description, project, tags or notes
+func FuzzyMatchTask(pattern string, task Task) bool {
+	fields := append([]string{task.Description, task.Project, task.Notes}, task.Tags...)
+	for _, field := range fields {
+		if FuzzyMatch(pattern, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// FuzzyMatch reports whether all characters of pattern appear in text in order,
+// ignoring case (e.g. "dsgn" matches "Work on design concept")
+func FuzzyMatch(pattern, text string) bool {
+	patternRunes := []rune(strings.ToLower(pattern))
+	if len(patternRunes) == 0 {
+		return true
+	}
+
+	i := 0
+	for _, r := range strings.ToLower(text) {
+		if unicode.ToLower(r) == patternRunes[i] {
+			i++
+			if i == len(patternRunes) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SetFilter parses and applies a filter expression to the task list.
+// An empty expression clears the filter.
+func (tm *TaskManager) SetFilter(expression string) error {
+	filter, err := ParseFilter(expression, time.Now())
+	if err != nil {
+		return fmt.Errorf("invalid filter: %w", err)
+	}
+	tm.Filter = filter
+	return nil
+}
+
+// ClearFilter removes any active filter
+func (tm *TaskManager) ClearFilter() {
+	tm.Filter = TaskFilter{}
+}
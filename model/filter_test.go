@@ -0,0 +1,81 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		text    string
+		want    bool
+	}{
+		{"", "anything", true},
+		{"dsgn", "Work on design concept", true},
+		{"DSGN", "work on design concept", true},
+		{"design", "Work on design concept", true},
+		{"ngised", "Work on design concept", false},
+		{"dsgnx", "Work on design concept", false},
+		{"über", "Über refactor", true},
+		{"abc", "", false},
+	}
+
+	for _, tt := range tests {
+		if got := FuzzyMatch(tt.pattern, tt.text); got != tt.want {
+			t.Errorf("FuzzyMatch(%q, %q) = %v, want %v", tt.pattern, tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestTaskFilterMatch(t *testing.T) {
+	now := time.Date(2026, 10, 18, 14, 0, 0, 0, time.Local)
+	today := StartOfDay(now)
+	tomorrow := today.AddDate(0, 0, 1)
+
+	review := Task{Description: "Review pull request", Project: "acme", Tags: []string{"code"}, Due: &today}
+	docs := Task{Description: "Write docs", Project: "acme", Tags: []string{"docs"}, Due: &tomorrow, Completed: true}
+	errand := Task{Description: "Buy milk", Notes: "oat, not cow"}
+
+	tests := []struct {
+		expression string
+		want       []bool // matches for review, docs and errand
+	}{
+		{"", []bool{true, true, true}},
+		{"project:acme", []bool{true, true, false}},
+		{"project:ACME", []bool{true, true, false}},
+		{"!project:acme", []bool{false, false, true}},
+		{"tag:docs", []bool{false, true, false}},
+		{"+code", []bool{true, false, false}},
+		{"done", []bool{false, true, false}},
+		{"!done", []bool{true, false, true}},
+		{"due<=today", []bool{true, false, false}},
+		{"due>today", []bool{false, true, false}},
+		{"due:tomorrow", []bool{false, true, false}},
+		{"rvw", []bool{true, false, false}},
+		{"oat", []bool{false, false, true}},
+		{"project:acme !done rvw", []bool{true, false, false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			filter, err := ParseFilter(tt.expression, now)
+			if err != nil {
+				t.Fatalf("ParseFilter(%q): %v", tt.expression, err)
+			}
+			for i, task := range []Task{review, docs, errand} {
+				if got := filter.Match(task); got != tt.want[i] {
+					t.Errorf("%q matching %q = %v, want %v", tt.expression, task.Description, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseFilterErrors(t *testing.T) {
+	for _, expression := range []string{"due<=someday", "due:2026-13-01"} {
+		if _, err := ParseFilter(expression, time.Now()); err == nil {
+			t.Errorf("ParseFilter(%q) succeeded, want an error", expression)
+		}
+	}
+}
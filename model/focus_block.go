@@ -0,0 +1,80 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// FocusBlock is a stretch of focus on one task, made of one or more focus sessions
+type FocusBlock struct {
+	// IDs of the sessions in the block, in order
+	SessionIDs []string
+	// Index of the block's first part within the first session (0 unless the session switched tasks)
+	Part int
+	// Task snapshot taken from the first session
+	TaskID          string
+	TaskDescription string
+	Project         string
+	Tags            []string
+	// Wall-clock start of the first session and end of the last one
+	Start time.Time
+	End   time.Time
+	// Focus time and completed pomodoros summed over the sessions, with the shares of
+	// pomodoros split with other tasks
+	Duration  time.Duration
+	Pomodoros float64
+}
+
+// UID returns an identifier for the block that stays the same across exports
+func (b FocusBlock) UID() string {
+	if b.Part == 0 {
+		return b.SessionIDs[0]
+	}
+	return fmt.Sprintf("%s-%d", b.SessionIDs[0], b.Part)
+}
+
+// FocusBlocks groups focus sessions into blocks in chronological order, ignoring breaks.
+// Sessions that switched tasks are split into one block per task, and with merge set,
+// consecutive sessions on the same task are merged into one block when the next one starts
+// within maxGap of the previous one ending (e.g. after a short break).
+func FocusBlocks(sessions []Session, merge bool, maxGap time.Duration) []FocusBlock {
+	blocks := make([]FocusBlock, 0)
+
+	for _, session := range sessions {
+		if !session.IsFocus() {
+			continue
+		}
+
+		for i, part := range session.Parts() {
+			if merge && len(blocks) > 0 {
+				last := &blocks[len(blocks)-1]
+				if last.TaskID == part.TaskID && !part.Start.After(last.End.Add(maxGap)) {
+					if last.SessionIDs[len(last.SessionIDs)-1] != part.ID {
+						last.SessionIDs = append(last.SessionIDs, part.ID)
+					}
+					last.Duration += part.Duration
+					if part.End.After(last.End) {
+						last.End = part.End
+					}
+					last.Pomodoros += part.Pomodoros()
+					continue
+				}
+			}
+
+			blocks = append(blocks, FocusBlock{
+				SessionIDs:      []string{part.ID},
+				Part:            i,
+				TaskID:          part.TaskID,
+				TaskDescription: part.TaskDescription,
+				Project:         part.Project,
+				Tags:            part.Tags,
+				Start:           part.Start,
+				End:             part.End,
+				Duration:        part.Duration,
+				Pomodoros:       part.Pomodoros(),
+			})
+		}
+	}
+
+	return blocks
+}
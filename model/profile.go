@@ -0,0 +1,154 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Profile is a named set of timer durations, e.g. "deep work 50/10/30"
+type Profile struct {
+	Name string `json:"name"`
+	// Durations in minutes
+	PomodoroDuration   int `json:"pomodoro_duration"`
+	ShortBreakDuration int `json:"short_break_duration"`
+	LongBreakDuration  int `json:"long_break_duration"`
+	// Projects and tags whose tasks switch the timer to this profile when they are started
+	Projects []string `json:"projects,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+// profileDurations matches the "focus/short/long" durations of a profile, the long break being optional
+var profileDurations = regexp.MustCompile(`^(\d+)/(\d+)(?:/(\d+))?$`)
+
+// DefaultProfiles returns the profiles offered before any are configured
+func DefaultProfiles() []Profile {
+	return []Profile{
+		{Name: "classic", PomodoroDuration: 25, ShortBreakDuration: 5, LongBreakDuration: 15},
+		{Name: "deep work", PomodoroDuration: 50, ShortBreakDuration: 10, LongBreakDuration: 30},
+		{Name: "admin", PomodoroDuration: 15, ShortBreakDuration: 3, LongBreakDuration: 10},
+	}
+}
+
+// Durations returns the durations of the profile, e.g. "50/10/30"
+func (p Profile) Durations() string {
+	return fmt.Sprintf("%d/%d/%d", p.PomodoroDuration, p.ShortBreakDuration, p.LongBreakDuration)
+}
+
+// String formats the profile as it is typed in the settings, e.g. "deep work 50/10/30 project:api +writing"
+func (p Profile) String() string {
+	parts := []string{p.Name, p.Durations()}
+	for _, project := range p.Projects {
+		parts = append(parts, "project:"+project)
+	}
+	for _, tag := range p.Tags {
+		parts = append(parts, "+"+tag)
+	}
+	return strings.Join(parts, " ")
+}
+
+// Matches reports whether the settings use the durations of the profile
+func (p Profile) Matches(settings Settings) bool {
+	return p.PomodoroDuration == settings.PomodoroDuration &&
+		p.ShortBreakDuration == settings.ShortBreakDuration &&
+		p.LongBreakDuration == settings.LongBreakDuration
+}
+
+// ParseProfiles parses profiles separated by semicolons, each a name followed by its durations
+// and optionally the projects and tags it is used for, e.g. "admin 15/3; deep work 50/10/30 +writing".
+// A missing long break is three times the short break.
+func ParseProfiles(input string) ([]Profile, error) {
+	profiles := make([]Profile, 0)
+	for _, item := range strings.Split(input, ";") {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+
+		var profile Profile
+		words := make([]string, 0)
+		for _, word := range strings.Fields(item) {
+			match := profileDurations.FindStringSubmatch(word)
+			switch {
+			case match != nil:
+				profile.PomodoroDuration, _ = strconv.Atoi(match[1])
+				profile.ShortBreakDuration, _ = strconv.Atoi(match[2])
+				profile.LongBreakDuration = 3 * profile.ShortBreakDuration
+				if match[3] != "" {
+					profile.LongBreakDuration, _ = strconv.Atoi(match[3])
+				}
+			case strings.HasPrefix(word, "project:") && len(word) > len("project:"):
+				profile.Projects = append(profile.Projects, strings.TrimPrefix(word, "project:"))
+			case strings.HasPrefix(word, "+") && len(word) > 1:
+				profile.Tags = append(profile.Tags, strings.TrimPrefix(word, "+"))
+			default:
+				words = append(words, word)
+			}
+		}
+		profile.Name = strings.Join(words, " ")
+
+		if profile.Name == "" {
+			return nil, fmt.Errorf("profile %q has no name", strings.TrimSpace(item))
+		}
+		if profile.PomodoroDuration < 1 || profile.ShortBreakDuration < 1 || profile.LongBreakDuration < 1 {
+			return nil, fmt.Errorf("profile %q needs durations in minutes, e.g. 25/5/15", profile.Name)
+		}
+		profiles = append(profiles, profile)
+	}
+	return profiles, nil
+}
+
+// FormatProfiles formats profiles as they are typed in the settings
+func FormatProfiles(profiles []Profile) string {
+	items := make([]string, 0, len(profiles))
+	for _, profile := range profiles {
+		items = append(items, profile.String())
+	}
+	return strings.Join(items, "; ")
+}
+
+// ActiveProfile returns the first profile whose durations the settings use
+func (s Settings) ActiveProfile() (Profile, bool) {
+	for _, profile := range s.Profiles {
+		if profile.Matches(s) {
+			return profile, true
+		}
+	}
+	return Profile{}, false
+}
+
+// NextProfile returns the profile after the active one, or the first if none is active
+func (s Settings) NextProfile() (Profile, bool) {
+	if len(s.Profiles) == 0 {
+		return Profile{}, false
+	}
+	for i, profile := range s.Profiles {
+		if profile.Matches(s) {
+			return s.Profiles[(i+1)%len(s.Profiles)], true
+		}
+	}
+	return s.Profiles[0], true
+}
+
+// ProfileForTask returns the profile used for a task's project, or else for one of its tags
+func (s Settings) ProfileForTask(project string, tags []string) (Profile, bool) {
+	if project != "" {
+		for _, profile := range s.Profiles {
+			for _, candidate := range profile.Projects {
+				if strings.EqualFold(candidate, project) {
+					return profile, true
+				}
+			}
+		}
+	}
+	for _, profile := range s.Profiles {
+		for _, candidate := range profile.Tags {
+			for _, tag := range tags {
+				if strings.EqualFold(candidate, tag) {
+					return profile, true
+				}
+			}
+		}
+	}
+	return Profile{}, false
+}
@@ -0,0 +1,206 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RecurrenceKind identifies how a recurring task repeats
+type RecurrenceKind string
+
+const (
+	// RecurDaily repeats every day
+	RecurDaily RecurrenceKind = "daily"
+	// RecurWeekdays repeats Monday to Friday
+	RecurWeekdays RecurrenceKind = "weekdays"
+	// RecurWeekly repeats on the given days of the week
+	RecurWeekly RecurrenceKind = "weekly"
+	// RecurInterval repeats every N days
+	RecurInterval RecurrenceKind = "interval"
+)
+
+// weekdayNames maps the short names accepted in recurrence rules to weekdays
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// Recurrence is the rule by which a task repeats once completed
+type Recurrence struct {
+	Kind RecurrenceKind `json:"kind"`
+	// Days of the week for weekly recurrence
+	Days []time.Weekday `json:"days,omitempty"`
+	// Number of days between occurrences for interval recurrence
+	Interval int `json:"interval,omitempty"`
+}
+
+// ParseRecurrence parses a recurrence rule: "daily", "weekdays",
+// a comma-separated list of days such as "mon,wed,fri", or "Nd" for every N days
+func ParseRecurrence(rule string) (*Recurrence, error) {
+	rule = strings.ToLower(strings.TrimSpace(rule))
+
+	switch rule {
+	case "daily":
+		return &Recurrence{Kind: RecurDaily}, nil
+	case "weekdays":
+		return &Recurrence{Kind: RecurWeekdays}, nil
+	}
+
+	if strings.HasSuffix(rule, "d") {
+		if interval, err := strconv.Atoi(strings.TrimSuffix(rule, "d")); err == nil {
+			if interval < 1 {
+				return nil, fmt.Errorf("invalid recurrence %q: interval must be at least 1 day", rule)
+			}
+			return &Recurrence{Kind: RecurInterval, Interval: interval}, nil
+		}
+	}
+
+	days, err := ParseWeekdays(rule)
+	if err != nil || len(days) == 0 {
+		return nil, fmt.Errorf("invalid recurrence %q: use daily, weekdays, mon,wed,fri or Nd", rule)
+	}
+	return &Recurrence{Kind: RecurWeekly, Days: days}, nil
+}
+
+// ParseWeekdays parses a comma-separated list of short day names such as "sat,sun".
+// An empty list yields no days.
+func ParseWeekdays(list string) ([]time.Weekday, error) {
+	days := make([]time.Weekday, 0)
+	for _, name := range strings.Split(strings.ToLower(list), ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		day, ok := weekdayNames[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown day %q: use mon, tue, wed, thu, fri, sat or sun", name)
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+// FormatWeekdays formats days as a comma-separated list of short day names
+func FormatWeekdays(days []time.Weekday) string {
+	names := make([]string, len(days))
+	for i, day := range days {
+		names[i] = strings.ToLower(day.String()[:3])
+	}
+	return strings.Join(names, ",")
+}
+
+// String returns the rule in the form accepted by ParseRecurrence
+func (r Recurrence) String() string {
+	switch r.Kind {
+	case RecurWeekly:
+		return FormatWeekdays(r.Days)
+	case RecurInterval:
+		return fmt.Sprintf("%dd", r.Interval)
+	default:
+		return string(r.Kind)
+	}
+}
+
+// Next returns the first date of the recurrence strictly after the given day
+func (r Recurrence) Next(after time.Time) time.Time {
+	day := StartOfDay(after)
+
+	switch r.Kind {
+	case RecurInterval:
+		interval := r.Interval
+		if interval < 1 {
+			interval = 1
+		}
+		return day.AddDate(0, 0, interval)
+
+	case RecurWeekdays:
+		next := day.AddDate(0, 0, 1)
+		for next.Weekday() == time.Saturday || next.Weekday() == time.Sunday {
+			next = next.AddDate(0, 0, 1)
+		}
+		return next
+
+	case RecurWeekly:
+		for offset := 1; offset <= 7; offset++ {
+			next := day.AddDate(0, 0, offset)
+			for _, weekday := range r.Days {
+				if next.Weekday() == weekday {
+					return next
+				}
+			}
+		}
+		// No days configured: fall back to weekly on the same day
+		return day.AddDate(0, 0, 7)
+
+	default:
+		return day.AddDate(0, 0, 1)
+	}
+}
+
+// spawnNextOccurrence creates the next occurrence of the recurring task at index i
+// once it has been completed. The new task starts with reset pomodoro counters and
+// is linked to the same series so their history stays together.
+func (tm *TaskManager) spawnNextOccurrence(i int, now time.Time) {
+	task := tm.Tasks[i]
+	if task.Recurrence == nil || task.NextOccurrenceID != "" {
+		return
+	}
+
+	// Schedule from the due date, unless it already lies in the past
+	base := StartOfDay(now)
+	if task.Due != nil && task.Due.After(base) {
+		base = *task.Due
+	}
+	due := task.Recurrence.Next(base)
+
+	next := NewTask(task.Description, task.PlannedPomodoros)
+	next.ParentID = task.ParentID
+	next.Notes = task.Notes
+	next.Project = task.Project
+	next.Tags = append([]string(nil), task.Tags...)
+	next.Priority = task.Priority
+	next.GitRepo = task.GitRepo
+	next.GitBranch = task.GitBranch
+	if task.Durations != nil {
+		durations := *task.Durations
+		next.Durations = &durations
+	}
+	next.Due = &due
+	recurrence := *task.Recurrence
+	next.Recurrence = &recurrence
+	next.SeriesID = task.SeriesID
+	if next.SeriesID == "" {
+		next.SeriesID = task.ID
+	}
+
+	tm.Tasks[i].SeriesID = next.SeriesID
+	tm.Tasks[i].NextOccurrenceID = next.ID
+	tm.Tasks = append(tm.Tasks, next)
+}
+
+// Series returns every occurrence of the recurring series a task belongs to, oldest first
+func (tm *TaskManager) Series(id string) []Task {
+	task, found := tm.GetTask(id)
+	if !found {
+		return nil
+	}
+	seriesID := task.SeriesID
+	if seriesID == "" {
+		seriesID = task.ID
+	}
+
+	series := make([]Task, 0)
+	for _, t := range tm.Tasks {
+		if t.ID == seriesID || t.SeriesID == seriesID {
+			series = append(series, t)
+		}
+	}
+	return series
+}
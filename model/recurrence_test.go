@@ -0,0 +1,147 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRecurrence(t *testing.T) {
+	tests := []struct {
+		rule    string
+		want    string
+		wantErr bool
+	}{
+		{rule: "daily", want: "daily"},
+		{rule: " Weekdays ", want: "weekdays"},
+		{rule: "mon,wed,fri", want: "mon,wed,fri"},
+		{rule: "SAT, sun", want: "sat,sun"},
+		{rule: "3d", want: "3d"},
+		{rule: "1d", want: "1d"},
+		{rule: "0d", wantErr: true},
+		{rule: "-2d", wantErr: true},
+		{rule: "monthly", wantErr: true},
+		{rule: "mon,funday", wantErr: true},
+		{rule: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			recurrence, err := ParseRecurrence(tt.rule)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseRecurrence(%q) = %v, want an error", tt.rule, recurrence)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRecurrence(%q): %v", tt.rule, err)
+			}
+			if got := recurrence.String(); got != tt.want {
+				t.Errorf("ParseRecurrence(%q).String() = %q, want %q", tt.rule, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecurrenceNext(t *testing.T) {
+	// 2026-10-16 is a Friday
+	friday := time.Date(2026, 10, 16, 15, 30, 0, 0, time.Local)
+	date := func(day int) time.Time {
+		return time.Date(2026, 10, day, 0, 0, 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		name       string
+		recurrence Recurrence
+		after      time.Time
+		want       time.Time
+	}{
+		{"daily", Recurrence{Kind: RecurDaily}, friday, date(17)},
+		{"weekdays skip the weekend", Recurrence{Kind: RecurWeekdays}, friday, date(19)},
+		{"weekdays from Saturday", Recurrence{Kind: RecurWeekdays}, date(17), date(19)},
+		{"weekdays midweek", Recurrence{Kind: RecurWeekdays}, date(14), date(15)},
+		{"weekly later this week", Recurrence{Kind: RecurWeekly, Days: []time.Weekday{time.Monday, time.Saturday}}, friday, date(17)},
+		{"weekly next week", Recurrence{Kind: RecurWeekly, Days: []time.Weekday{time.Monday, time.Wednesday}}, friday, date(19)},
+		{"weekly same day is a week later", Recurrence{Kind: RecurWeekly, Days: []time.Weekday{time.Friday}}, friday, date(23)},
+		{"weekly without days", Recurrence{Kind: RecurWeekly}, friday, date(23)},
+		{"interval", Recurrence{Kind: RecurInterval, Interval: 3}, friday, date(19)},
+		{"interval across months", Recurrence{Kind: RecurInterval, Interval: 20}, friday, time.Date(2026, 11, 5, 0, 0, 0, 0, time.Local)},
+		{"invalid interval", Recurrence{Kind: RecurInterval}, friday, date(17)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.recurrence.Next(tt.after); !got.Equal(tt.want) {
+				t.Errorf("Next(%s) = %s, want %s", tt.after.Format("Mon 2006-01-02"), got.Format("Mon 2006-01-02"), tt.want.Format("Mon 2006-01-02"))
+			}
+		})
+	}
+}
+
+func TestCompletingRecurringTaskSpawnsNextOccurrence(t *testing.T) {
+	tests := []struct {
+		name     string
+		complete func(tm *TaskManager, id string)
+	}{
+		{"completed", func(tm *TaskManager, id string) { tm.ToggleTaskComplete(id) }},
+		{"planned pomodoros done", func(tm *TaskManager, id string) {
+			tm.AddCompletedPomodoro(id)
+			tm.AddCompletedPomodoro(id)
+		}},
+		{"pomodoro shares add up", func(tm *TaskManager, id string) {
+			for i := 0; i < 6; i++ {
+				tm.AddPomodoroShare(id, 1.0/3)
+			}
+		}},
+		{"pomodoros and a share", func(tm *TaskManager, id string) {
+			tm.AddPomodoroShare(id, 0.5)
+			tm.AddCompletedPomodoro(id)
+			tm.AddPomodoroShare(id, 0.5)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := NewTaskManager()
+			task := tm.AddTask("Water plants", 2)
+			recurrence, _ := ParseRecurrence("daily")
+			tm.Tasks[0].Recurrence = recurrence
+			tm.Tasks[0].Priority = "H"
+
+			tt.complete(tm, task.ID)
+
+			if len(tm.Tasks) != 2 {
+				t.Fatalf("got %d tasks, want 2", len(tm.Tasks))
+			}
+			previous, next := tm.Tasks[0], tm.Tasks[1]
+			if !previous.Completed {
+				t.Errorf("previous occurrence still open: %+v", previous)
+			}
+			if previous.NextOccurrenceID != next.ID || previous.SeriesID != task.ID || next.SeriesID != task.ID {
+				t.Errorf("occurrences not linked: %+v, %+v", previous, next)
+			}
+			if next.CompletedPomodoros != 0 || next.PomodoroFraction != 0 || next.Completed {
+				t.Errorf("next occurrence not reset: %+v", next)
+			}
+			if next.Priority != "H" || next.Recurrence == nil {
+				t.Errorf("next occurrence lost its details: %+v", next)
+			}
+
+			// Overrunning the plan doesn't spawn a second occurrence
+			tm.AddCompletedPomodoro(task.ID)
+			if len(tm.Tasks) != 2 {
+				t.Errorf("got %d tasks, want 2", len(tm.Tasks))
+			}
+		})
+	}
+}
+
+func TestReachingPlanKeepsOtherTasksOpen(t *testing.T) {
+	tm := NewTaskManager()
+	task := tm.AddTask("Write docs", 1)
+	tm.AddCompletedPomodoro(task.ID)
+
+	if len(tm.Tasks) != 1 || tm.Tasks[0].Completed {
+		t.Errorf("tasks %+v, want the task open at its plan", tm.Tasks)
+	}
+}
@@ -0,0 +1,96 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Reminder is a nudge repeated during focus periods, e.g. every 20 minutes to look away
+type Reminder struct {
+	// Minutes of focus between reminders
+	Every   int    `json:"every"`
+	Message string `json:"message"`
+}
+
+// String formats the reminder as it is typed in the settings, e.g. "20 Look away for 20 seconds"
+func (r Reminder) String() string {
+	return fmt.Sprintf("%d %s", r.Every, r.Message)
+}
+
+// ParseReminders parses reminders separated by semicolons, each the minutes between reminders
+// followed by the message, e.g. "20 Look away for 20 seconds; 60 Drink some water"
+func ParseReminders(input string) ([]Reminder, error) {
+	reminders := make([]Reminder, 0)
+	for _, item := range strings.Split(input, ";") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		minutes, message, _ := strings.Cut(item, " ")
+		every, err := strconv.Atoi(minutes)
+		if err != nil || every < 1 {
+			return nil, fmt.Errorf("reminder %q must start with the minutes between reminders", item)
+		}
+		message = strings.TrimSpace(message)
+		if message == "" {
+			return nil, fmt.Errorf("reminder %q has no message", item)
+		}
+		reminders = append(reminders, Reminder{Every: every, Message: message})
+	}
+	return reminders, nil
+}
+
+// FormatReminders formats reminders as they are typed in the settings
+func FormatReminders(reminders []Reminder) string {
+	items := make([]string, 0, len(reminders))
+	for _, reminder := range reminders {
+		items = append(items, reminder.String())
+	}
+	return strings.Join(items, "; ")
+}
+
+// ReminderScheduler works out when reminders are due during a focus period. It only counts
+// time the timer runs in focus mode, so pauses and breaks delay reminders, and it never
+// changes the timer.
+type ReminderScheduler struct {
+	// SessionStart of the focus period the counts belong to
+	session time.Time
+	// Number of times each reminder has fired in the focus period
+	fired map[Reminder]int
+}
+
+// NewReminderScheduler creates a reminder scheduler
+func NewReminderScheduler() *ReminderScheduler {
+	return &ReminderScheduler{fired: make(map[Reminder]int)}
+}
+
+// Due returns the reminders that have come due since it was last called, meant to be called
+// on every tick after the timer is updated
+func (s *ReminderScheduler) Due(timer *Timer, reminders []Reminder) []Reminder {
+	if timer.Mode != FocusMode || timer.State != TimerRunning {
+		return nil
+	}
+
+	// Each focus period starts counting afresh
+	if !timer.SessionStart.Equal(s.session) {
+		s.session = timer.SessionStart
+		s.fired = make(map[Reminder]int)
+	}
+
+	focused := timer.Duration - timer.Remaining
+	due := make([]Reminder, 0)
+	for _, reminder := range reminders {
+		if reminder.Every < 1 {
+			continue
+		}
+		times := int(focused / (time.Duration(reminder.Every) * time.Minute))
+		if times > s.fired[reminder] {
+			s.fired[reminder] = times
+			due = append(due, reminder)
+		}
+	}
+	return due
+}
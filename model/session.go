@@ -0,0 +1,290 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/segmentio/ksuid"
+)
+
+// Session is a single focus or break period recorded in the history
+type Session struct {
+	ID string `json:"id"`
+	// Focus, short break or long break
+	Mode TimerMode `json:"mode"`
+	// The task worked on during a focus session, or the one most of it went to (empty if none)
+	TaskID string `json:"task_id,omitempty"`
+	// Snapshot of the task at the time of the session, so history outlives the task
+	TaskDescription string   `json:"task_description,omitempty"`
+	Project         string   `json:"project,omitempty"`
+	Tags            []string `json:"tags,omitempty"`
+	// Wall-clock start and end of the session, including any pauses
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	// The length the timer was set to
+	Planned time.Duration `json:"planned"`
+	// Time actually spent running, excluding pauses
+	Duration time.Duration `json:"duration"`
+	// Whether the session counted: a finished pomodoro or a break taken in full
+	Completed bool `json:"completed"`
+	// Whether a break was skipped before it ended
+	Skipped bool `json:"skipped,omitempty"`
+	// Times skipping or cutting short a break was attempted in strict mode
+	SkipAttempts int `json:"skip_attempts,omitempty"`
+	// Whether the focus period was counted up in Flowtime mode (Planned is then the pomodoro
+	// duration) or the break was earned by one
+	Flowtime bool `json:"flowtime,omitempty"`
+	// Commits made during a focus session on a task linked to a git repository
+	Commits []Commit `json:"commits,omitempty"`
+	// Stretches of a focus session that switched tasks, in order (empty if it didn't)
+	Segments []TaskSegment `json:"segments,omitempty"`
+}
+
+// TaskSegment is a stretch of a focus session spent on one task
+type TaskSegment struct {
+	// The task worked on (empty if none), with a snapshot of it as for sessions
+	TaskID          string   `json:"task_id,omitempty"`
+	TaskDescription string   `json:"task_description,omitempty"`
+	Project         string   `json:"project,omitempty"`
+	Tags            []string `json:"tags,omitempty"`
+	// Wall-clock start and end of the stretch, including any pauses
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	// Time spent on the task, excluding pauses
+	Duration time.Duration `json:"duration"`
+	// Share of the pomodoro credited to the task by the attribution rule (0 if the session didn't count)
+	Pomodoros float64 `json:"pomodoros,omitempty"`
+}
+
+// Commit is a git commit recorded in the history
+type Commit struct {
+	Hash    string    `json:"hash"`
+	Subject string    `json:"subject"`
+	At      time.Time `json:"at"`
+}
+
+// ShortHash returns the abbreviated commit hash
+func (c Commit) ShortHash() string {
+	if len(c.Hash) > 7 {
+		return c.Hash[:7]
+	}
+	return c.Hash
+}
+
+// IsFocus reports whether the session was a focus period
+func (s Session) IsFocus() bool {
+	return s.Mode == FocusMode
+}
+
+// IsBreak reports whether the session was a short or long break
+func (s Session) IsBreak() bool {
+	return s.Mode == ShortBreakMode || s.Mode == LongBreakMode
+}
+
+// hasSegment reports whether part of the session was spent on a task
+func (s Session) hasSegment(taskID string) bool {
+	for _, segment := range s.Segments {
+		if segment.TaskID == taskID {
+			return true
+		}
+	}
+	return false
+}
+
+// Pomodoros returns the pomodoros a session counts for: one for a completed focus session, or
+// the share credited to its task for a part of one
+func (s Session) Pomodoros() float64 {
+	if !s.IsFocus() || !s.Completed {
+		return 0
+	}
+	if len(s.Segments) == 0 {
+		return 1
+	}
+	var pomodoros float64
+	for _, segment := range s.Segments {
+		pomodoros += segment.Pomodoros
+	}
+	return pomodoros
+}
+
+// Parts splits a session that switched tasks into one session per stretch, for totals and
+// exports by task, project or tag. Each part keeps its stretch as its only segment, so that
+// Pomodoros returns the share of the pomodoro credited to it. A session on a single task is
+// returned as it is.
+func (s Session) Parts() []Session {
+	if len(s.Segments) == 0 {
+		return []Session{s}
+	}
+
+	segments := append([]TaskSegment(nil), s.Segments...)
+	if s.Completed && s.Pomodoros() == 0 {
+		// Sessions recorded before shares were kept credit the task most of the time went to
+		for i := range segments {
+			if segments[i].TaskID == s.TaskID {
+				segments[i].Pomodoros = 1
+				break
+			}
+		}
+	}
+
+	parts := make([]Session, 0, len(segments))
+	for _, segment := range segments {
+		part := s
+		part.Segments = []TaskSegment{segment}
+		part.TaskID = segment.TaskID
+		part.TaskDescription = segment.TaskDescription
+		part.Project = segment.Project
+		part.Tags = segment.Tags
+		part.Duration = segment.Duration
+		if !segment.Start.IsZero() {
+			part.Start = segment.Start
+			part.End = segment.End
+		}
+		parts = append(parts, part)
+	}
+	return parts
+}
+
+// History is the log of recorded timer sessions. It is global: it is kept
+// even when tasks are archived, trashed or deleted.
+type History struct {
+	Sessions []Session
+	OnRecord func(session Session)
+	// Called before a session is stored, to add details such as commits
+	BeforeRecord func(session *Session)
+}
+
+// NewHistory creates a new, empty session history
+func NewHistory() *History {
+	return &History{
+		Sessions: make([]Session, 0),
+		OnRecord: nil,
+	}
+}
+
+// LoadSessions loads sessions into the history
+func (h *History) LoadSessions(sessions []Session) {
+	h.Sessions = sessions
+}
+
+// GetSessions returns all sessions for saving
+func (h *History) GetSessions() []Session {
+	return h.Sessions
+}
+
+// Record adds a session to the history, assigning it an ID, and returns it
+func (h *History) Record(session Session) Session {
+	if session.ID == "" {
+		session.ID = ksuid.New().String()
+	}
+	if h.BeforeRecord != nil {
+		h.BeforeRecord(&session)
+	}
+	h.Sessions = append(h.Sessions, session)
+
+	if h.OnRecord != nil {
+		h.OnRecord(session)
+	}
+	return session
+}
+
+// AttachCommits adds commits to a recorded session, calling the record handler again so that
+// the change is saved. It returns false if there is no session with the ID.
+func (h *History) AttachCommits(sessionID string, commits []Commit) bool {
+	for i, session := range h.Sessions {
+		if session.ID == sessionID {
+			h.Sessions[i].Commits = append(session.Commits, commits...)
+			if h.OnRecord != nil {
+				h.OnRecord(h.Sessions[i])
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// RegisterRecordHandler sets a function to be called whenever a session is recorded
+func (h *History) RegisterRecordHandler(handler func(session Session)) {
+	h.OnRecord = handler
+}
+
+// RegisterBeforeRecordHandler sets a function that can add details to each session before it is stored,
+// or start looking them up
+func (h *History) RegisterBeforeRecordHandler(handler func(session *Session)) {
+	h.BeforeRecord = handler
+}
+
+// TaskCommits returns the commits recorded in the sessions of a task, newest first
+func (h *History) TaskCommits(taskID string) []Commit {
+	commits := make([]Commit, 0)
+	for _, session := range h.TaskSessions(taskID) {
+		commits = append(commits, session.Commits...)
+	}
+	sort.SliceStable(commits, func(i, j int) bool {
+		return commits[i].At.After(commits[j].At)
+	})
+	return commits
+}
+
+// SessionsBetween returns the sessions that started in [from, to), oldest first
+func (h *History) SessionsBetween(from, to time.Time) []Session {
+	sessions := make([]Session, 0)
+	for _, session := range h.Sessions {
+		if !session.Start.Before(from) && session.Start.Before(to) {
+			sessions = append(sessions, session)
+		}
+	}
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].Start.Before(sessions[j].Start)
+	})
+	return sessions
+}
+
+// TaskSessions returns all sessions recorded against a task or partly spent on it, oldest first
+func (h *History) TaskSessions(taskID string) []Session {
+	sessions := make([]Session, 0)
+	for _, session := range h.Sessions {
+		if session.TaskID == taskID || session.hasSegment(taskID) {
+			sessions = append(sessions, session)
+		}
+	}
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].Start.Before(sessions[j].Start)
+	})
+	return sessions
+}
+
+// String returns the name of the timer mode
+func (m TimerMode) String() string {
+	switch m {
+	case FocusMode:
+		return "focus"
+	case ShortBreakMode:
+		return "short_break"
+	case LongBreakMode:
+		return "long_break"
+	default:
+		return fmt.Sprintf("mode(%d)", int(m))
+	}
+}
+
+// MarshalText encodes the timer mode by name
+func (m TimerMode) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalText decodes a timer mode from its name
+func (m *TimerMode) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "focus":
+		*m = FocusMode
+	case "short_break":
+		*m = ShortBreakMode
+	case "long_break":
+		*m = LongBreakMode
+	default:
+		return fmt.Errorf("unknown timer mode %q", string(text))
+	}
+	return nil
+}
@@ -14,8 +14,55 @@ type Settings struct {
 	LongBreakDuration int `json:"long_break_duration"`
 	// Automatically start breaks after pomodoro completes
 	AutoStartBreaks bool `json:"auto_start_breaks"`
+	// Automatically start the next pomodoro after a break, following a countdown
+	AutoStartPomodoros bool `json:"auto_start_pomodoros,omitempty"`
+	// Seconds to count down before a pomodoro starts automatically (0 starts it at once)
+	AutoStartGrace int `json:"auto_start_grace"`
+	// How a pomodoro spent on several tasks is credited: AttributeSplit (the default),
+	// AttributeMajority or AttributeLast
+	PomodoroAttribution string `json:"pomodoro_attribution,omitempty"`
+	// Breaks start automatically and can't be skipped or cut short
+	StrictMode bool `json:"strict_mode,omitempty"`
+	// Count focus time up until stopped (Flowtime) instead of down from the pomodoro duration
+	Flowtime bool `json:"flowtime,omitempty"`
+	// Minutes of Flowtime focus earning a minute of break
+	FlowtimeBreakRatio int `json:"flowtime_break_ratio"`
+	// Number of pomodoros to complete each day (0 disables the goal)
+	DailyGoal int `json:"daily_goal"`
+	// Days of the week on which missing the goal does not break the streak
+	RestDays []time.Weekday `json:"rest_days"`
+	// Days a task stays in the trash before it is purged (0 keeps it forever)
+	TrashRetentionDays int `json:"trash_retention_days"`
+	// Saved task filter expressions, recalled with the number keys
+	SavedFilters []string `json:"saved_filters,omitempty"`
+	// Named sets of durations to switch between
+	Profiles []Profile `json:"profiles"`
+	// Nudges repeated during focus periods, such as looking away from the screen
+	Reminders []Reminder `json:"reminders,omitempty"`
+	// Activities suggested for breaks, one picked per break
+	BreakActivities []string `json:"break_activities"`
+	// Show an animated box breathing guide during short breaks
+	BreathingGuide bool `json:"breathing_guide,omitempty"`
+	// Command run with the message of each reminder, e.g. "notify-send Pomodoro" (empty for none)
+	ReminderCommand string `json:"reminder_command,omitempty"`
 }
 
+// Rules for crediting a pomodoro spent on several tasks. Time is always credited as spent.
+const (
+	// AttributeSplit shares the pomodoro between the tasks in proportion to the time spent on each
+	AttributeSplit = "split"
+	// AttributeMajority credits the whole pomodoro to the task most of it was spent on
+	AttributeMajority = "majority"
+	// AttributeLast credits the whole pomodoro to the task worked on when it ended
+	AttributeLast = "last"
+)
+
+// AttributionRules lists the pomodoro attribution rules in the order the settings view cycles through them
+var AttributionRules = []string{AttributeSplit, AttributeMajority, AttributeLast}
+
+// MaxSavedFilters is the number of saved filters that can be recalled with keys 1-9
+const MaxSavedFilters = 9
+
 // DefaultSettings creates and returns default settings
 func DefaultSettings() Settings {
 	return Settings{
@@ -23,6 +70,13 @@ func DefaultSettings() Settings {
 		ShortBreakDuration: 5,     // Default: 5 minutes
 		LongBreakDuration:  30,    // Default: 30 minutes
 		AutoStartBreaks:    false, // Default: don't auto-start breaks
+		FlowtimeBreakRatio: 5,     // Default: a minute of break per 5 minutes of focus
+		AutoStartGrace:     10,    // Default: 10 seconds to get ready for the next pomodoro
+		TrashRetentionDays: 30,    // Default: purge the trash after 30 days
+		DailyGoal:          8,     // Default: 8 pomodoros a day
+		RestDays:           []time.Weekday{time.Saturday, time.Sunday},
+		Profiles:           DefaultProfiles(),
+		BreakActivities:    DefaultBreakActivities(),
 	}
 }
 
@@ -40,3 +94,47 @@ func (s Settings) GetShortBreakDuration() time.Duration {
 func (s Settings) GetLongBreakDuration() time.Duration {
 	return time.Duration(s.LongBreakDuration) * time.Minute
 }
+
+// GetPomodoroAttribution returns the rule for crediting pomodoros spent on several tasks
+func (s Settings) GetPomodoroAttribution() string {
+	for _, rule := range AttributionRules {
+		if s.PomodoroAttribution == rule {
+			return rule
+		}
+	}
+	return AttributeSplit
+}
+
+// GetAutoStartGrace returns the countdown before a pomodoro starts automatically as time.Duration
+func (s Settings) GetAutoStartGrace() time.Duration {
+	return time.Duration(s.AutoStartGrace) * time.Second
+}
+
+// GetFlowtimeBreak returns the break earned by a Flowtime focus period, rounded to the
+// minute and at least a minute long
+func (s Settings) GetFlowtimeBreak(focus time.Duration) time.Duration {
+	ratio := s.FlowtimeBreakRatio
+	if ratio < 1 {
+		ratio = 1
+	}
+	minutes := int((focus/time.Duration(ratio) + time.Minute/2) / time.Minute)
+	if minutes < 1 {
+		minutes = 1
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// GetTrashRetention returns how long trashed tasks are kept as time.Duration
+func (s Settings) GetTrashRetention() time.Duration {
+	return time.Duration(s.TrashRetentionDays) * 24 * time.Hour
+}
+
+// IsRestDay reports whether the given weekday is a configured rest day
+func (s Settings) IsRestDay(day time.Weekday) bool {
+	for _, restDay := range s.RestDays {
+		if restDay == day {
+			return true
+		}
+	}
+	return false
+}
@@ -1,5 +1,10 @@
 package model
 
+import (
+	"strings"
+	"time"
+)
+
 // SettingsManager handles the application settings
 type SettingsManager struct {
 	Settings Settings
@@ -41,6 +46,151 @@ func (sm *SettingsManager) SetLongBreakDuration(minutes int) {
 	sm.notifyChange()
 }
 
+// SetAutoStartPomodoros sets whether the next pomodoro starts automatically after a break
+func (sm *SettingsManager) SetAutoStartPomodoros(enabled bool) {
+	sm.Settings.AutoStartPomodoros = enabled
+	sm.notifyChange()
+}
+
+// SetAutoStartGrace sets the countdown before a pomodoro starts automatically
+func (sm *SettingsManager) SetAutoStartGrace(seconds int) {
+	if seconds < 0 {
+		seconds = 0
+	}
+	sm.Settings.AutoStartGrace = seconds
+	sm.notifyChange()
+}
+
+// CyclePomodoroAttribution switches to the next rule for crediting pomodoros spent on several tasks
+func (sm *SettingsManager) CyclePomodoroAttribution() {
+	next := AttributionRules[0]
+	for i, rule := range AttributionRules {
+		if rule == sm.Settings.GetPomodoroAttribution() {
+			next = AttributionRules[(i+1)%len(AttributionRules)]
+		}
+	}
+	// Attribution only applies when a pomodoro ends, so the change handler is not called
+	sm.Settings.PomodoroAttribution = next
+}
+
+// SetStrictMode turns strict mode, which enforces breaks, on or off
+func (sm *SettingsManager) SetStrictMode(enabled bool) {
+	sm.Settings.StrictMode = enabled
+	sm.notifyChange()
+}
+
+// SetFlowtime switches between Flowtime and fixed-length focus periods
+func (sm *SettingsManager) SetFlowtime(enabled bool) {
+	sm.Settings.Flowtime = enabled
+	sm.notifyChange()
+}
+
+// SetFlowtimeBreakRatio sets how many minutes of Flowtime focus earn a minute of break
+func (sm *SettingsManager) SetFlowtimeBreakRatio(ratio int) {
+	if ratio < 1 {
+		ratio = 1
+	}
+	sm.Settings.FlowtimeBreakRatio = ratio
+	sm.notifyChange()
+}
+
+// SetTrashRetentionDays sets how many days trashed tasks are kept (0 keeps them forever)
+func (sm *SettingsManager) SetTrashRetentionDays(days int) {
+	if days < 0 {
+		days = 0
+	}
+	sm.Settings.TrashRetentionDays = days
+	sm.notifyChange()
+}
+
+// SetDailyGoal sets the number of pomodoros to complete each day (0 disables the goal)
+func (sm *SettingsManager) SetDailyGoal(pomodoros int) {
+	if pomodoros < 0 {
+		pomodoros = 0
+	}
+	sm.Settings.DailyGoal = pomodoros
+	sm.notifyChange()
+}
+
+// SetRestDays sets the days on which missing the daily goal does not break the streak
+func (sm *SettingsManager) SetRestDays(days []time.Weekday) {
+	sm.Settings.RestDays = days
+	sm.notifyChange()
+}
+
+// SetProfiles replaces the profiles that can be switched between
+func (sm *SettingsManager) SetProfiles(profiles []Profile) {
+	// Profiles don't affect the timer until one is applied, so the change handler is not called
+	sm.Settings.Profiles = profiles
+}
+
+// SetBreakActivities replaces the activities suggested for breaks
+func (sm *SettingsManager) SetBreakActivities(activities []string) {
+	// Activities are picked when a break begins, so the change handler is not called
+	sm.Settings.BreakActivities = activities
+}
+
+// SetBreathingGuide shows or hides the box breathing guide during short breaks
+func (sm *SettingsManager) SetBreathingGuide(enabled bool) {
+	sm.Settings.BreathingGuide = enabled
+}
+
+// SetReminders replaces the reminders repeated during focus periods
+func (sm *SettingsManager) SetReminders(reminders []Reminder) {
+	// Reminders don't affect the timer, so the change handler is not called
+	sm.Settings.Reminders = reminders
+}
+
+// SetReminderCommand sets the command run with the message of each reminder
+func (sm *SettingsManager) SetReminderCommand(command string) {
+	sm.Settings.ReminderCommand = strings.TrimSpace(command)
+}
+
+// ApplyProfile switches the timer durations to those of a profile
+func (sm *SettingsManager) ApplyProfile(profile Profile) {
+	sm.Settings.PomodoroDuration = profile.PomodoroDuration
+	sm.Settings.ShortBreakDuration = profile.ShortBreakDuration
+	sm.Settings.LongBreakDuration = profile.LongBreakDuration
+	sm.notifyChange()
+}
+
+// AddSavedFilter saves a filter expression so it can be recalled later.
+// It returns false if the expression is empty, already saved, or no slots are left.
+func (sm *SettingsManager) AddSavedFilter(expression string) bool {
+	expression = strings.TrimSpace(expression)
+	if expression == "" || len(sm.Settings.SavedFilters) >= MaxSavedFilters {
+		return false
+	}
+	for _, saved := range sm.Settings.SavedFilters {
+		if saved == expression {
+			return false
+		}
+	}
+	// Saved filters don't affect the timer, so the change handler (which resets it) is not called
+	sm.Settings.SavedFilters = append(sm.Settings.SavedFilters, expression)
+	return true
+}
+
+// RemoveSavedFilter deletes a saved filter expression and reports whether it existed
+func (sm *SettingsManager) RemoveSavedFilter(expression string) bool {
+	expression = strings.TrimSpace(expression)
+	for i, saved := range sm.Settings.SavedFilters {
+		if saved == expression {
+			sm.Settings.SavedFilters = append(sm.Settings.SavedFilters[:i], sm.Settings.SavedFilters[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// SavedFilter returns the saved filter in the given 1-based slot
+func (sm *SettingsManager) SavedFilter(slot int) (string, bool) {
+	if slot < 1 || slot > len(sm.Settings.SavedFilters) {
+		return "", false
+	}
+	return sm.Settings.SavedFilters[slot-1], true
+}
+
 // RegisterChangeHandler sets a function to be called when settings change
 func (sm *SettingsManager) RegisterChangeHandler(handler func()) {
 	sm.OnChange = handler
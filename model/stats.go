@@ -0,0 +1,193 @@
+package model
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Names used in breakdowns for sessions without a project or tags
+const (
+	NoProjectName = "(no project)"
+	UntaggedName  = "(untagged)"
+)
+
+// DayStats summarises the focus sessions of a single day (or week, for weekly totals)
+type DayStats struct {
+	Date      time.Time
+	Pomodoros int
+	FocusTime time.Duration
+}
+
+// BreakdownEntry summarises the focus sessions of one project or tag
+type BreakdownEntry struct {
+	Name string
+	// Completed pomodoros, including shares of pomodoros split with other projects or tags
+	Pomodoros float64
+	FocusTime time.Duration
+}
+
+// Stats summarises the session history over a period of days
+type Stats struct {
+	// First day of the period and the day after its last day
+	From time.Time
+	To   time.Time
+	// One entry per day of the period, oldest first
+	Days []DayStats
+	// Completed pomodoros and total focus time, including interrupted sessions
+	Pomodoros     int
+	FocusTime     time.Duration
+	FocusSessions int
+	// Breaks taken in full, and all breaks recorded including skipped or cut short ones
+	BreaksTaken    int
+	BreaksRecorded int
+	// Attempts to skip breaks made in strict mode
+	SkipAttempts int
+	// Focus time per project and per tag, largest first
+	ByProject []BreakdownEntry
+	ByTag     []BreakdownEntry
+}
+
+// StartOfDay returns local midnight of the day containing t
+func StartOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// ComputeStats summarises the sessions of the given number of days ending with the day of now
+func ComputeStats(history *History, days int, now time.Time) Stats {
+	if days < 1 {
+		days = 1
+	}
+	to := StartOfDay(now).AddDate(0, 0, 1)
+	from := to.AddDate(0, 0, -days)
+
+	stats := Stats{
+		From: from,
+		To:   to,
+		Days: make([]DayStats, days),
+	}
+	for i := range stats.Days {
+		stats.Days[i].Date = from.AddDate(0, 0, i)
+	}
+
+	projects := make(map[string]*BreakdownEntry)
+	tags := make(map[string]*BreakdownEntry)
+
+	for _, session := range history.SessionsBetween(from, to) {
+		if session.IsBreak() {
+			stats.BreaksRecorded++
+			stats.SkipAttempts += session.SkipAttempts
+			if session.Completed {
+				stats.BreaksTaken++
+			}
+			continue
+		}
+
+		pomodoros := 0
+		if session.Completed {
+			pomodoros = 1
+		}
+
+		// Days are counted by calendar date, which copes with daylight saving changes
+		day := dayIndex(from, session.Start)
+		if day >= 0 && day < days {
+			stats.Days[day].Pomodoros += pomodoros
+			stats.Days[day].FocusTime += session.Duration
+		}
+
+		stats.Pomodoros += pomodoros
+		stats.FocusTime += session.Duration
+		stats.FocusSessions++
+
+		// Sessions that switched tasks are split between their projects and tags
+		for _, part := range session.Parts() {
+			pomodoros := part.Pomodoros()
+
+			project := part.Project
+			if project == "" {
+				project = NoProjectName
+			}
+			addToBreakdown(projects, project, pomodoros, part.Duration)
+
+			if len(part.Tags) == 0 {
+				addToBreakdown(tags, UntaggedName, pomodoros, part.Duration)
+			}
+			for _, tag := range part.Tags {
+				addToBreakdown(tags, tag, pomodoros, part.Duration)
+			}
+		}
+	}
+
+	stats.ByProject = sortedBreakdown(projects)
+	stats.ByTag = sortedBreakdown(tags)
+	return stats
+}
+
+// AverageSession returns the mean length of the focus sessions in the period
+func (s Stats) AverageSession() time.Duration {
+	if s.FocusSessions == 0 {
+		return 0
+	}
+	return s.FocusTime / time.Duration(s.FocusSessions)
+}
+
+// BreakCompliance returns the fraction of recorded breaks that were taken in full,
+// and false if no breaks were recorded
+func (s Stats) BreakCompliance() (float64, bool) {
+	if s.BreaksRecorded == 0 {
+		return 0, false
+	}
+	return float64(s.BreaksTaken) / float64(s.BreaksRecorded), true
+}
+
+// Weeks returns the per-day stats summed into consecutive seven-day periods,
+// aligned so the last period ends on the last day
+func (s Stats) Weeks() []DayStats {
+	weeks := make([]DayStats, 0, len(s.Days)/7+1)
+	for end := len(s.Days); end > 0; end -= 7 {
+		start := end - 7
+		if start < 0 {
+			start = 0
+		}
+
+		week := DayStats{Date: s.Days[start].Date}
+		for _, day := range s.Days[start:end] {
+			week.Pomodoros += day.Pomodoros
+			week.FocusTime += day.FocusTime
+		}
+		weeks = append([]DayStats{week}, weeks...)
+	}
+	return weeks
+}
+
+// dayIndex returns the number of calendar days between from and t.
+// Rounding absorbs the 23 and 25 hour days around daylight saving changes.
+func dayIndex(from, t time.Time) int {
+	return int(math.Round(StartOfDay(t).Sub(from).Hours() / 24))
+}
+
+// addToBreakdown adds a session's totals to the named breakdown entry
+func addToBreakdown(entries map[string]*BreakdownEntry, name string, pomodoros float64, focusTime time.Duration) {
+	entry, ok := entries[name]
+	if !ok {
+		entry = &BreakdownEntry{Name: name}
+		entries[name] = entry
+	}
+	entry.Pomodoros += pomodoros
+	entry.FocusTime += focusTime
+}
+
+// sortedBreakdown returns breakdown entries ordered by focus time, largest first
+func sortedBreakdown(entries map[string]*BreakdownEntry) []BreakdownEntry {
+	sorted := make([]BreakdownEntry, 0, len(entries))
+	for _, entry := range entries {
+		sorted = append(sorted, *entry)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].FocusTime != sorted[j].FocusTime {
+			return sorted[i].FocusTime > sorted[j].FocusTime
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
@@ -0,0 +1,77 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// Streak describes progress towards the daily goal and the run of days meeting it
+type Streak struct {
+	// Pomodoros required per day (0 if the goal is disabled)
+	Goal int
+	// Pomodoros completed today
+	Today int
+	// Consecutive days meeting the goal, ending today or yesterday
+	Current int
+	// Longest run of consecutive days meeting the goal
+	Longest int
+}
+
+// GoalMetToday reports whether today's pomodoros reach the goal
+func (s Streak) GoalMetToday() bool {
+	return s.Goal > 0 && s.Today >= s.Goal
+}
+
+// ComputeStreak computes today's progress and the current and longest streaks.
+// Rest days never break a streak, and count towards it only if the goal was met.
+// Today only breaks the streak once it is over, so an unfinished day keeps it alive.
+func ComputeStreak(history *History, settings Settings, now time.Time) Streak {
+	today := StartOfDay(now)
+	streak := Streak{Goal: settings.DailyGoal}
+
+	// Count completed pomodoros per day
+	counts := make(map[time.Time]int)
+	first := today
+	for _, session := range history.Sessions {
+		if !session.IsFocus() || !session.Completed {
+			continue
+		}
+		day := StartOfDay(session.Start)
+		counts[day]++
+		if day.Before(first) {
+			first = day
+		}
+	}
+	streak.Today = counts[today]
+
+	if streak.Goal <= 0 {
+		return streak
+	}
+
+	// Walk forward from the first recorded day, tracking runs of days that meet the goal
+	run := 0
+	for day := first; !day.After(today); day = day.AddDate(0, 0, 1) {
+		switch {
+		case counts[day] >= streak.Goal:
+			run++
+		case settings.IsRestDay(day.Weekday()), day.Equal(today):
+			// Rest days and the unfinished current day keep the run going
+		default:
+			run = 0
+		}
+		if run > streak.Longest {
+			streak.Longest = run
+		}
+	}
+	streak.Current = run
+
+	return streak
+}
+
+// Summary returns a one-line plain-text description of today's progress and the streak
+func (s Streak) Summary() string {
+	if s.Goal <= 0 {
+		return fmt.Sprintf("%d pomodoros today", s.Today)
+	}
+	return fmt.Sprintf("%d/%d pomodoros today · %d-day streak (best %d)", s.Today, s.Goal, s.Current, s.Longest)
+}
@@ -2,6 +2,8 @@ package model
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/segmentio/ksuid"
@@ -15,10 +17,136 @@ type Task struct {
 	Completed   bool      `json:"completed"`
 	// Total number of pomodoros planned for this task
 	PlannedPomodoros int `json:"planned_pomodoros"`
-	// Number of pomodoros completed for this task
+	// Number of pomodoros completed for this task (may exceed the plan)
 	CompletedPomodoros int `json:"completed_pomodoros"`
+	// Share of a pomodoro credited on top of CompletedPomodoros, from pomodoros split between tasks
+	PomodoroFraction float64 `json:"pomodoro_fraction,omitempty"`
+	// Pomodoros planned when the task was created, before any re-estimates
+	OriginalEstimate int `json:"original_estimate,omitempty"`
+	// Changes made to the planned pomodoros after the task was created
+	Reestimates []Reestimate `json:"reestimates,omitempty"`
+	// When the task was completed (nil if it is not completed)
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
 	// Total time spent on this task
 	TimeSpent time.Duration `json:"time_spent"`
+	// ID of the parent task (empty for top-level tasks)
+	ParentID string `json:"parent_id,omitempty"`
+	// Whether the subtasks of this task are hidden in the task list
+	Collapsed bool `json:"collapsed,omitempty"`
+	// Free-form Markdown notes, e.g. the next step to take when resuming
+	Notes string `json:"notes,omitempty"`
+	// Project the task belongs to (empty if none)
+	Project string `json:"project,omitempty"`
+	// Free-form tags attached to the task
+	Tags []string `json:"tags,omitempty"`
+	// Due date of the task (nil if none)
+	Due *time.Time `json:"due,omitempty"`
+	// Archived tasks are hidden from the task list but still counted in statistics
+	Archived bool `json:"archived,omitempty"`
+	// When the task was moved to the trash (nil if it is not in the trash)
+	TrashedAt *time.Time `json:"trashed_at,omitempty"`
+	// Rule by which the task repeats once completed (nil for one-off tasks)
+	Recurrence *Recurrence `json:"recurrence,omitempty"`
+	// ID of the first occurrence of a recurring series (empty if not part of one)
+	SeriesID string `json:"series_id,omitempty"`
+	// ID of the occurrence spawned when this one was completed
+	NextOccurrenceID string `json:"next_occurrence_id,omitempty"`
+	// Priority: H, M or L (empty if none)
+	Priority string `json:"priority,omitempty"`
+	// UUID of the Taskwarrior task this task was imported from (empty if none)
+	TaskwarriorUUID string `json:"taskwarrior_uuid,omitempty"`
+	// Annotation last written to the Taskwarrior task by sync
+	TaskwarriorAnnotation string `json:"taskwarrior_annotation,omitempty"`
+	// TODO or FIXME comment the task was imported from (nil if none)
+	CodeComment *CodeComment `json:"code_comment,omitempty"`
+	// Top-level directory of the git repository the task is worked on in (empty if none)
+	GitRepo string `json:"git_repo,omitempty"`
+	// Branch the task is worked on (empty if none)
+	GitBranch string `json:"git_branch,omitempty"`
+	// Timer durations used while working on the task instead of the settings (nil if none)
+	Durations *TaskDurations `json:"durations,omitempty"`
+}
+
+// Reestimate records a change of a task's planned pomodoros
+type Reestimate struct {
+	At   time.Time `json:"at"`
+	From int       `json:"from"`
+	To   int       `json:"to"`
+}
+
+// CodeComment locates a TODO or FIXME comment in a source tree
+type CodeComment struct {
+	// Absolute path of the directory that was scanned
+	Root string `json:"root"`
+	// Path of the file relative to Root, with forward slashes
+	File string `json:"file"`
+	Line int    `json:"line"`
+	// The comment as found, e.g. "FIXME: handle empty input"
+	Text string `json:"text"`
+}
+
+// String returns the file:line reference of the comment
+func (c CodeComment) String() string {
+	return fmt.Sprintf("%s:%d", c.File, c.Line)
+}
+
+// TaskDurations overrides the timer durations of the settings for a task, in minutes.
+// Zero durations are taken from the settings.
+type TaskDurations struct {
+	Pomodoro   int `json:"pomodoro,omitempty"`
+	ShortBreak int `json:"short_break,omitempty"`
+	LongBreak  int `json:"long_break,omitempty"`
+}
+
+// ParseTaskDurations parses durations in minutes such as "15", "50/10" or "50/10/30"
+// (focus, short break, long break); "-" leaves a duration to the settings, e.g. "-/10"
+func ParseTaskDurations(value string) (*TaskDurations, error) {
+	parts := strings.Split(value, "/")
+	if len(parts) > 3 {
+		return nil, fmt.Errorf("invalid durations %q: use focus/short/long minutes, e.g. 50/10/30", value)
+	}
+
+	minutes := make([]int, 3)
+	for i, part := range parts {
+		if part == "-" {
+			continue
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid durations %q: use focus/short/long minutes, e.g. 50/10/30", value)
+		}
+		minutes[i] = n
+	}
+
+	durations := &TaskDurations{Pomodoro: minutes[0], ShortBreak: minutes[1], LongBreak: minutes[2]}
+	if *durations == (TaskDurations{}) {
+		return nil, fmt.Errorf("invalid durations %q: no duration given", value)
+	}
+	return durations, nil
+}
+
+// String formats the durations as they are typed, e.g. "50/10" or "-/10"
+func (d TaskDurations) String() string {
+	parts := []string{strconv.Itoa(d.Pomodoro), strconv.Itoa(d.ShortBreak), strconv.Itoa(d.LongBreak)}
+	for i, minutes := range []int{d.Pomodoro, d.ShortBreak, d.LongBreak} {
+		if minutes == 0 {
+			parts[i] = "-"
+		}
+	}
+	// Trailing durations left to the settings are omitted
+	for len(parts) > 1 && parts[len(parts)-1] == "-" {
+		parts = parts[:len(parts)-1]
+	}
+	return strings.Join(parts, "/")
+}
+
+// TaskAttributes holds the optional attributes that can be typed alongside a task description
+type TaskAttributes struct {
+	Project    string
+	Tags       []string
+	Due        *time.Time
+	Recurrence *Recurrence
+	Durations  *TaskDurations
 }
 
 // NewTask creates a new task with default values
@@ -33,23 +161,182 @@ func NewTask(description string, plannedPomodoros int) Task {
 		Completed:          false,
 		PlannedPomodoros:   plannedPomodoros,
 		CompletedPomodoros: 0,
+		OriginalEstimate:   plannedPomodoros,
 		TimeSpent:          0,
 	}
 }
 
+// ParseTaskInput splits free-form task input into its description and the
+// "project:name", "+tag", "due:date", "every:rule" and "timer:minutes" tokens it contains
+func ParseTaskInput(input string) (string, TaskAttributes) {
+	var attrs TaskAttributes
+	words := make([]string, 0)
+
+	for _, word := range strings.Fields(input) {
+		switch {
+		case strings.HasPrefix(word, "project:") && len(word) > len("project:"):
+			attrs.Project = strings.TrimPrefix(word, "project:")
+		case strings.HasPrefix(word, "+") && len(word) > 1:
+			attrs.Tags = append(attrs.Tags, strings.TrimPrefix(word, "+"))
+		case strings.HasPrefix(word, "due:"):
+			due, err := ParseDate(strings.TrimPrefix(word, "due:"), time.Now())
+			if err != nil {
+				// Keep unparseable tokens as part of the description
+				words = append(words, word)
+				continue
+			}
+			attrs.Due = &due
+		case strings.HasPrefix(word, "every:"):
+			recurrence, err := ParseRecurrence(strings.TrimPrefix(word, "every:"))
+			if err != nil {
+				words = append(words, word)
+				continue
+			}
+			attrs.Recurrence = recurrence
+		case strings.HasPrefix(word, "timer:"):
+			durations, err := ParseTaskDurations(strings.TrimPrefix(word, "timer:"))
+			if err != nil {
+				words = append(words, word)
+				continue
+			}
+			attrs.Durations = durations
+		default:
+			words = append(words, word)
+		}
+	}
+
+	return strings.Join(words, " "), attrs
+}
+
+// ParseDate parses "today", "tomorrow", "yesterday" or a YYYY-MM-DD date
+// relative to now and returns midnight of that day in local time
+func ParseDate(value string, now time.Time) (time.Time, error) {
+	today := StartOfDay(now)
+
+	switch strings.ToLower(value) {
+	case "today":
+		return today, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+
+	date, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q: use today, tomorrow, yesterday or YYYY-MM-DD", value)
+	}
+	return date, nil
+}
+
+// HasTag reports whether the task has the given tag (case-insensitive)
+func (t Task) HasTag(tag string) bool {
+	for _, taskTag := range t.Tags {
+		if strings.EqualFold(taskTag, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// AttributesLabel returns the project, tags, due date and other attributes formatted for display
+func (t Task) AttributesLabel() string {
+	parts := make([]string, 0)
+	if t.Project != "" {
+		parts = append(parts, "project:"+t.Project)
+	}
+	for _, tag := range t.Tags {
+		parts = append(parts, "+"+tag)
+	}
+	if t.Due != nil {
+		parts = append(parts, "due:"+t.Due.Format("2006-01-02"))
+	}
+	if t.Recurrence != nil {
+		parts = append(parts, "every:"+t.Recurrence.String())
+	}
+	if t.Durations != nil {
+		parts = append(parts, "timer:"+t.Durations.String())
+	}
+	if t.Priority != "" {
+		parts = append(parts, "priority:"+t.Priority)
+	}
+	if t.CodeComment != nil {
+		parts = append(parts, t.CodeComment.String())
+	}
+	if t.GitBranch != "" {
+		parts = append(parts, "branch:"+t.GitBranch)
+	}
+	return strings.Join(parts, " ")
+}
+
+// InTrash reports whether the task has been moved to the trash
+func (t Task) InTrash() bool {
+	return t.TrashedAt != nil
+}
+
+// IsListed reports whether the task belongs in the main task list (neither archived nor trashed)
+func (t Task) IsListed() bool {
+	return !t.Archived && !t.InTrash()
+}
+
 // ToggleComplete toggles the completed status of the task
 func (t *Task) ToggleComplete() {
-	t.Completed = !t.Completed
+	t.SetCompleted(!t.Completed, time.Now())
+}
+
+// SetCompleted marks the task as completed at the given time, or as not completed
+func (t *Task) SetCompleted(completed bool, at time.Time) {
+	t.Completed = completed
+	if completed {
+		t.CompletedAt = &at
+	} else {
+		t.CompletedAt = nil
+	}
 }
 
-// AddCompletedPomodoro increments the completed pomodoro count
+// AddCompletedPomodoro increments the completed pomodoro count.
+// Tasks are not completed automatically, so pomodoros beyond the plan count as overrun.
 func (t *Task) AddCompletedPomodoro() {
 	t.CompletedPomodoros++
-	if t.CompletedPomodoros >= t.PlannedPomodoros {
-		t.Completed = true
+}
+
+// AddPomodoroShare credits a share of a pomodoro, counting a completed pomodoro each time the
+// shares add up to a whole one
+func (t *Task) AddPomodoroShare(share float64) {
+	t.PomodoroFraction += share
+	// Allow for rounding, so that shares such as thirds add up to whole pomodoros
+	for t.PomodoroFraction > 1-pomodoroShareTolerance {
+		t.CompletedPomodoros++
+		t.PomodoroFraction--
+	}
+	if t.PomodoroFraction < pomodoroShareTolerance {
+		t.PomodoroFraction = 0
 	}
 }
 
+// pomodoroShareTolerance is the rounding allowed when adding up pomodoro shares
+const pomodoroShareTolerance = 1e-6
+
+// InitialEstimate returns the pomodoros planned before any re-estimates
+func (t Task) InitialEstimate() int {
+	if t.OriginalEstimate > 0 {
+		return t.OriginalEstimate
+	}
+	// Tasks created before estimates were recorded
+	if len(t.Reestimates) > 0 {
+		return t.Reestimates[0].From
+	}
+	return t.PlannedPomodoros
+}
+
+// Overrun returns the number of pomodoros spent beyond the current plan (0 if within it)
+func (t Task) Overrun() int {
+	if t.CompletedPomodoros > t.PlannedPomodoros {
+		return t.CompletedPomodoros - t.PlannedPomodoros
+	}
+	return 0
+}
+
 // AddTimeSpent adds duration to the time spent on this task
 func (t *Task) AddTimeSpent(duration time.Duration) {
 	t.TimeSpent += duration
@@ -57,8 +344,13 @@ func (t *Task) AddTimeSpent(duration time.Duration) {
 
 // FormattedTimeSpent returns the formatted time spent on the task
 func (t Task) FormattedTimeSpent() string {
-	hours := int(t.TimeSpent.Hours())
-	minutes := int(t.TimeSpent.Minutes()) % 60
+	return FormatDuration(t.TimeSpent)
+}
+
+// FormatDuration formats a duration as hours and minutes, e.g. "1h 15m" or "50m"
+func FormatDuration(d time.Duration) string {
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
 
 	if hours > 0 {
 		return fmt.Sprintf("%dh %dm", hours, minutes)
@@ -66,7 +358,17 @@ func (t Task) FormattedTimeSpent() string {
 	return fmt.Sprintf("%dm", minutes)
 }
 
+// Pomodoros returns the completed pomodoros including shares of pomodoros split with other tasks
+func (t Task) Pomodoros() float64 {
+	return float64(t.CompletedPomodoros) + t.PomodoroFraction
+}
+
+// FormatPomodoros formats a pomodoro count to one decimal place, leaving out ".0", e.g. "3" or "2.5"
+func FormatPomodoros(pomodoros float64) string {
+	return strings.TrimSuffix(strconv.FormatFloat(pomodoros, 'f', 1, 64), ".0")
+}
+
 // PomodoroProgress returns a string representation of pomodoro progress
 func (t Task) PomodoroProgress() string {
-	return fmt.Sprintf("[%d/%d]", t.CompletedPomodoros, t.PlannedPomodoros)
+	return fmt.Sprintf("[%s/%d]", FormatPomodoros(t.Pomodoros()), t.PlannedPomodoros)
 }
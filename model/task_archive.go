@@ -0,0 +1,132 @@
+package model
+
+import (
+	"time"
+)
+
+// ArchiveTask archives a completed task and its subtasks, hiding them from the
+// task list while keeping their history for statistics
+func (tm *TaskManager) ArchiveTask(id string) bool {
+	task, found := tm.GetTask(id)
+	if !found || !task.Completed || task.InTrash() {
+		return false
+	}
+
+	for _, subtreeID := range tm.subtreeIDs(id) {
+		tm.updateByID(subtreeID, func(t *Task) {
+			t.Archived = true
+		})
+	}
+	return true
+}
+
+// TrashTask moves a task and its subtasks to the trash, from where they can be
+// restored until they are purged
+func (tm *TaskManager) TrashTask(id string, now time.Time) bool {
+	task, found := tm.GetTask(id)
+	if !found || task.InTrash() {
+		return false
+	}
+
+	for _, subtreeID := range tm.subtreeIDs(id) {
+		tm.updateByID(subtreeID, func(t *Task) {
+			if t.TrashedAt == nil {
+				trashedAt := now
+				t.TrashedAt = &trashedAt
+			}
+		})
+	}
+	return true
+}
+
+// RestoreTask brings an archived or trashed task and its subtasks back to the task list.
+// Its ancestors are restored too so the task does not end up orphaned.
+func (tm *TaskManager) RestoreTask(id string) bool {
+	if _, found := tm.GetTask(id); !found {
+		return false
+	}
+
+	ids := tm.subtreeIDs(id)
+	for _, ancestor := range tm.TaskPath(id) {
+		ids = append(ids, ancestor.ID)
+	}
+	for _, restoreID := range ids {
+		tm.updateByID(restoreID, func(t *Task) {
+			t.Archived = false
+			t.TrashedAt = nil
+		})
+	}
+	return true
+}
+
+// PurgeTrash permanently deletes tasks that have been in the trash for longer
+// than the retention period and returns how many were deleted.
+// A retention of zero or less keeps trashed tasks forever.
+func (tm *TaskManager) PurgeTrash(retention time.Duration, now time.Time) int {
+	if retention <= 0 {
+		return 0
+	}
+
+	remaining := make([]Task, 0, len(tm.Tasks))
+	for _, task := range tm.Tasks {
+		if task.InTrash() && now.Sub(*task.TrashedAt) > retention {
+			continue
+		}
+		remaining = append(remaining, task)
+	}
+
+	purged := len(tm.Tasks) - len(remaining)
+	tm.Tasks = remaining
+	return purged
+}
+
+// EmptyTrash permanently deletes every task in the trash and returns how many were deleted
+func (tm *TaskManager) EmptyTrash() int {
+	remaining := make([]Task, 0, len(tm.Tasks))
+	for _, task := range tm.Tasks {
+		if !task.InTrash() {
+			remaining = append(remaining, task)
+		}
+	}
+
+	purged := len(tm.Tasks) - len(remaining)
+	tm.Tasks = remaining
+	return purged
+}
+
+// ArchivedTasks returns archived tasks that are not in the trash
+func (tm *TaskManager) ArchivedTasks() []Task {
+	filtered := make([]Task, 0)
+	for _, task := range tm.Tasks {
+		if task.Archived && !task.InTrash() {
+			filtered = append(filtered, task)
+		}
+	}
+	return filtered
+}
+
+// TrashedTasks returns the tasks in the trash
+func (tm *TaskManager) TrashedTasks() []Task {
+	filtered := make([]Task, 0)
+	for _, task := range tm.Tasks {
+		if task.InTrash() {
+			filtered = append(filtered, task)
+		}
+	}
+	return filtered
+}
+
+// subtreeIDs returns the ID of a task followed by the IDs of all its subtasks
+func (tm *TaskManager) subtreeIDs(id string) []string {
+	return append([]string{id}, tm.descendantIDs(id)...)
+}
+
+// updateByID applies a change to the task with the given ID
+func (tm *TaskManager) updateByID(id string, change func(t *Task)) {
+	for i := range tm.Tasks {
+		if tm.Tasks[i].ID == id {
+			change(&tm.Tasks[i])
+			return
+		}
+	}
+}
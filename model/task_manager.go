@@ -8,6 +8,8 @@ import (
 type TaskManager struct {
 	Tasks         []Task
 	ShowCompleted bool
+	// Active search/filter; while non-empty it replaces the ShowCompleted toggle
+	Filter TaskFilter
 }
 
 // NewTaskManager creates a new task manager
@@ -18,9 +20,10 @@ func NewTaskManager() *TaskManager {
 	}
 }
 
-// LoadTasks loads tasks into the TaskManager
+// LoadTasks loads tasks into the TaskManager, moving tasks caught in a parent cycle to the top level
 func (tm *TaskManager) LoadTasks(tasks []Task) {
 	tm.Tasks = tasks
+	tm.breakParentCycles()
 }
 
 // GetTasks returns all tasks for saving
@@ -35,6 +38,38 @@ func (tm *TaskManager) AddTask(description string, plannedPomodoros int) Task {
 	return task
 }
 
+// FindByTaskwarriorUUID retrieves the task imported from the Taskwarrior task with the given UUID
+func (tm *TaskManager) FindByTaskwarriorUUID(uuid string) (Task, bool) {
+	for _, task := range tm.Tasks {
+		if uuid != "" && task.TaskwarriorUUID == uuid {
+			return task, true
+		}
+	}
+	return Task{}, false
+}
+
+// FindByGitBranch finds the open task linked to a branch of a git repository
+func (tm *TaskManager) FindByGitBranch(repo, branch string) (Task, bool) {
+	for _, task := range tm.Tasks {
+		if task.GitRepo == repo && task.GitBranch == branch && !task.Completed && !task.InTrash() {
+			return task, true
+		}
+	}
+	return Task{}, false
+}
+
+// SetGitBranch links a task to a branch of a git repository by ID (empty values unlink it) and returns the updated task
+func (tm *TaskManager) SetGitBranch(id, repo, branch string) (Task, bool) {
+	for i, task := range tm.Tasks {
+		if task.ID == id {
+			tm.Tasks[i].GitRepo = repo
+			tm.Tasks[i].GitBranch = branch
+			return tm.Tasks[i], true
+		}
+	}
+	return Task{}, false
+}
+
 // GetTask retrieves a task by ID
 func (tm *TaskManager) GetTask(id string) (Task, bool) {
 	for i, task := range tm.Tasks {
@@ -56,16 +91,53 @@ func (tm *TaskManager) UpdateTask(task Task) bool {
 	return false
 }
 
-// DeleteTask removes a task by ID
-func (tm *TaskManager) DeleteTask(id string) bool {
+// SetNotes replaces a task's notes by ID and returns the updated task
+func (tm *TaskManager) SetNotes(id string, notes string) (Task, bool) {
 	for i, task := range tm.Tasks {
 		if task.ID == id {
-			// Remove the task by appending everything before and after it
-			tm.Tasks = append(tm.Tasks[:i], tm.Tasks[i+1:]...)
-			return true
+			tm.Tasks[i].Notes = notes
+			return tm.Tasks[i], true
 		}
 	}
-	return false
+	return Task{}, false
+}
+
+// SetAttributes replaces a task's project, tags, due date, recurrence and durations by ID and returns the updated task
+func (tm *TaskManager) SetAttributes(id string, attrs TaskAttributes) (Task, bool) {
+	for i, task := range tm.Tasks {
+		if task.ID == id {
+			tm.Tasks[i].Project = attrs.Project
+			tm.Tasks[i].Tags = attrs.Tags
+			tm.Tasks[i].Due = attrs.Due
+			tm.Tasks[i].Recurrence = attrs.Recurrence
+			tm.Tasks[i].Durations = attrs.Durations
+			return tm.Tasks[i], true
+		}
+	}
+	return Task{}, false
+}
+
+// DeleteTask permanently removes a task and all of its subtasks by ID,
+// including their pomodoro history. Use TrashTask for a recoverable delete.
+func (tm *TaskManager) DeleteTask(id string) bool {
+	if _, found := tm.GetTask(id); !found {
+		return false
+	}
+
+	// Collect the task and its whole subtree
+	doomed := map[string]bool{id: true}
+	for _, descendantID := range tm.descendantIDs(id) {
+		doomed[descendantID] = true
+	}
+
+	remaining := make([]Task, 0, len(tm.Tasks))
+	for _, task := range tm.Tasks {
+		if !doomed[task.ID] {
+			remaining = append(remaining, task)
+		}
+	}
+	tm.Tasks = remaining
+	return true
 }
 
 // ToggleShowCompleted toggles whether completed tasks are shown
@@ -73,62 +145,134 @@ func (tm *TaskManager) ToggleShowCompleted() {
 	tm.ShowCompleted = !tm.ShowCompleted
 }
 
-// FilteredTasks returns tasks filtered according to current settings
+// FilteredTasks returns listed tasks filtered according to current settings
 func (tm *TaskManager) FilteredTasks() []Task {
-	if tm.ShowCompleted {
-		return tm.Tasks
-	}
-
 	filtered := make([]Task, 0)
 	for _, task := range tm.Tasks {
-		if !task.Completed {
+		if tm.isShown(task) {
 			filtered = append(filtered, task)
 		}
 	}
 	return filtered
 }
 
-// IncompleteTasks returns only incomplete tasks
+// isShown reports whether a listed task passes the active filter, or the
+// ShowCompleted toggle when no filter is active
+func (tm *TaskManager) isShown(task Task) bool {
+	if !task.IsListed() {
+		return false
+	}
+	if !tm.Filter.Empty() {
+		return tm.Filter.Match(task)
+	}
+	return tm.ShowCompleted || !task.Completed
+}
+
+// IncompleteTasks returns only incomplete listed tasks
 func (tm *TaskManager) IncompleteTasks() []Task {
 	filtered := make([]Task, 0)
 	for _, task := range tm.Tasks {
-		if !task.Completed {
+		if !task.Completed && task.IsListed() {
 			filtered = append(filtered, task)
 		}
 	}
 	return filtered
 }
 
-// CompletedTasks returns only completed tasks
+// CompletedTasks returns only completed listed tasks
 func (tm *TaskManager) CompletedTasks() []Task {
 	filtered := make([]Task, 0)
 	for _, task := range tm.Tasks {
-		if task.Completed {
+		if task.Completed && task.IsListed() {
 			filtered = append(filtered, task)
 		}
 	}
 	return filtered
 }
 
-// ToggleTaskComplete toggles a task's completion status by ID and returns the updated task
+// ToggleTaskComplete toggles a task's completion status by ID and returns the updated task.
+// Completing a recurring task spawns its next occurrence.
 func (tm *TaskManager) ToggleTaskComplete(id string) (Task, bool) {
 	for i, task := range tm.Tasks {
 		if task.ID == id {
-			tm.Tasks[i].Completed = !task.Completed
+			tm.Tasks[i].SetCompleted(!task.Completed, time.Now())
+			if tm.Tasks[i].Completed {
+				tm.spawnNextOccurrence(i, time.Now())
+			}
 			return tm.Tasks[i], true
 		}
 	}
 	return Task{}, false
 }
 
-// AddCompletedPomodoro increments a task's completed pomodoro count by ID and returns the updated task
+// AddCompletedPomodoro increments a task's completed pomodoro count by ID and returns the updated task.
+// The task stays open when it reaches its plan so that overruns can be measured, but a recurring
+// task is completed then, moving on to its next occurrence.
 func (tm *TaskManager) AddCompletedPomodoro(id string) (Task, bool) {
 	for i, task := range tm.Tasks {
 		if task.ID == id {
-			tm.Tasks[i].CompletedPomodoros++
-			if tm.Tasks[i].CompletedPomodoros >= task.PlannedPomodoros {
-				tm.Tasks[i].Completed = true
+			tm.Tasks[i].AddCompletedPomodoro()
+			tm.completeOccurrenceOnPlanReached(i)
+			return tm.Tasks[i], true
+		}
+	}
+	return Task{}, false
+}
+
+// AddPomodoroShare credits a share of a pomodoro to a task by ID and returns the updated task
+func (tm *TaskManager) AddPomodoroShare(id string, share float64) (Task, bool) {
+	for i, task := range tm.Tasks {
+		if task.ID == id {
+			tm.Tasks[i].AddPomodoroShare(share)
+			tm.completeOccurrenceOnPlanReached(i)
+			return tm.Tasks[i], true
+		}
+	}
+	return Task{}, false
+}
+
+// completeOccurrenceOnPlanReached completes the recurring task at index i once its planned
+// pomodoros, including shares of split ones, are done, and spawns its next occurrence, so
+// that only one occurrence of the series is open at a time
+func (tm *TaskManager) completeOccurrenceOnPlanReached(i int) {
+	task := tm.Tasks[i]
+	if task.Recurrence == nil || task.Completed || task.Pomodoros() < float64(task.PlannedPomodoros) {
+		return
+	}
+	now := time.Now()
+	tm.Tasks[i].SetCompleted(true, now)
+	tm.spawnNextOccurrence(i, now)
+}
+
+// Reestimate changes a task's planned pomodoros by ID, recording the change, and returns the updated task.
+// Repeated changes on the same day are merged into a single re-estimate.
+func (tm *TaskManager) Reestimate(id string, planned int, now time.Time) (Task, bool) {
+	if planned < 1 {
+		planned = 1
+	}
+	for i, task := range tm.Tasks {
+		if task.ID == id {
+			if planned == task.PlannedPomodoros {
+				return task, true
 			}
+			if task.OriginalEstimate == 0 {
+				tm.Tasks[i].OriginalEstimate = task.InitialEstimate()
+			}
+
+			last := len(task.Reestimates) - 1
+			if last >= 0 && StartOfDay(task.Reestimates[last].At).Equal(StartOfDay(now)) {
+				// Merge with today's re-estimate, dropping it if the plan is back where it started
+				if task.Reestimates[last].From == planned {
+					tm.Tasks[i].Reestimates = task.Reestimates[:last]
+				} else {
+					tm.Tasks[i].Reestimates[last].At = now
+					tm.Tasks[i].Reestimates[last].To = planned
+				}
+			} else {
+				tm.Tasks[i].Reestimates = append(task.Reestimates, Reestimate{At: now, From: task.PlannedPomodoros, To: planned})
+			}
+
+			tm.Tasks[i].PlannedPomodoros = planned
 			return tm.Tasks[i], true
 		}
 	}
@@ -0,0 +1,58 @@
+package model
+
+// queueOrder returns the tasks that pomodoros are worked on in planned order: the listed tasks
+// without subtasks, in task list order, regardless of collapsing and filters
+func (tm *TaskManager) queueOrder() []Task {
+	order := make([]Task, 0, len(tm.Tasks))
+	seen := make(map[string]bool)
+
+	var walk func(task Task)
+	walk = func(task Task) {
+		if seen[task.ID] || !task.IsListed() {
+			return
+		}
+		seen[task.ID] = true
+
+		children := tm.listedChildren(task.ID)
+		if len(children) == 0 {
+			order = append(order, task)
+		}
+		for _, child := range children {
+			walk(child)
+		}
+	}
+
+	for _, task := range tm.Tasks {
+		if tm.isRoot(task) {
+			walk(task)
+		}
+	}
+	return order
+}
+
+// NextQueuedTask returns the first task after the given one in planned order that is still to be
+// worked on. The queue ends with the last task of the list rather than wrapping around, so tasks
+// skipped above the current one are left alone.
+func (tm *TaskManager) NextQueuedTask(afterID string) (Task, bool) {
+	order := tm.queueOrder()
+
+	start := 0
+	for i, task := range order {
+		if task.ID == afterID {
+			start = i + 1
+		}
+	}
+
+	for _, task := range order[start:] {
+		if !task.queueDone() && task.ID != afterID {
+			return task, true
+		}
+	}
+	return Task{}, false
+}
+
+// queueDone reports whether the queue moves on from a task: once it is completed or its planned
+// pomodoros are done, as tasks are not completed automatically
+func (t Task) queueDone() bool {
+	return t.Completed || t.CompletedPomodoros >= t.PlannedPomodoros
+}
@@ -0,0 +1,360 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TaskNode is a task together with its position in the task hierarchy
+type TaskNode struct {
+	Task Task
+	// Nesting level of the task (0 for top-level tasks)
+	Depth int
+	// Whether the task has any subtasks
+	HasChildren bool
+	// Counters rolled up from the task and all of its subtasks
+	Totals TaskTotals
+}
+
+// TaskTotals holds pomodoro and time counters aggregated over a task subtree
+type TaskTotals struct {
+	PlannedPomodoros   int
+	CompletedPomodoros int
+	PomodoroFraction   float64
+	TimeSpent          time.Duration
+}
+
+// Pomodoros returns the total completed pomodoros including shares of split pomodoros
+func (tt TaskTotals) Pomodoros() float64 {
+	return float64(tt.CompletedPomodoros) + tt.PomodoroFraction
+}
+
+// FormattedTimeSpent returns the formatted total time spent
+func (tt TaskTotals) FormattedTimeSpent() string {
+	return FormatDuration(tt.TimeSpent)
+}
+
+// PomodoroProgress returns a string representation of the total pomodoro progress
+func (tt TaskTotals) PomodoroProgress() string {
+	return fmt.Sprintf("[%s/%d]", FormatPomodoros(tt.Pomodoros()), tt.PlannedPomodoros)
+}
+
+// AddSubtask adds a new task as a child of the given parent task
+func (tm *TaskManager) AddSubtask(parentID, description string, plannedPomodoros int) (Task, bool) {
+	if _, found := tm.GetTask(parentID); !found {
+		return Task{}, false
+	}
+
+	task := NewTask(description, plannedPomodoros)
+	task.ParentID = parentID
+	tm.Tasks = append(tm.Tasks, task)
+	return task, true
+}
+
+// Children returns the direct subtasks of a task in their stored order
+func (tm *TaskManager) Children(id string) []Task {
+	children := make([]Task, 0)
+	for _, task := range tm.Tasks {
+		if task.ParentID == id {
+			children = append(children, task)
+		}
+	}
+	return children
+}
+
+// HasChildren reports whether a task has any subtasks
+func (tm *TaskManager) HasChildren(id string) bool {
+	for _, task := range tm.Tasks {
+		if task.ParentID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// IsLeaf reports whether a task exists and has no subtasks.
+// Only leaf tasks can be run by the timer; parents accrue their totals from them.
+func (tm *TaskManager) IsLeaf(id string) bool {
+	if _, found := tm.GetTask(id); !found {
+		return false
+	}
+	return !tm.HasChildren(id)
+}
+
+// Totals returns the counters of a task rolled up with those of all its subtasks
+func (tm *TaskManager) Totals(id string) TaskTotals {
+	return tm.totals(id, make(map[string]bool))
+}
+
+// totals rolls up the counters of a task's subtree, skipping tasks already seen so that a
+// parent cycle in a hand-edited file can't recurse forever
+func (tm *TaskManager) totals(id string, seen map[string]bool) TaskTotals {
+	var totals TaskTotals
+	task, found := tm.GetTask(id)
+	if !found || seen[id] {
+		return totals
+	}
+	seen[id] = true
+
+	totals.PlannedPomodoros = task.PlannedPomodoros
+	totals.CompletedPomodoros = task.CompletedPomodoros
+	totals.PomodoroFraction = task.PomodoroFraction
+	totals.TimeSpent = task.TimeSpent
+
+	for _, child := range tm.Children(id) {
+		// Archived subtasks still count; trashed ones do not
+		if child.InTrash() {
+			continue
+		}
+		childTotals := tm.totals(child.ID, seen)
+		totals.PlannedPomodoros += childTotals.PlannedPomodoros
+		totals.CompletedPomodoros += childTotals.CompletedPomodoros
+		totals.PomodoroFraction += childTotals.PomodoroFraction
+		totals.TimeSpent += childTotals.TimeSpent
+	}
+	return totals
+}
+
+// ToggleCollapsed toggles whether a task's subtasks are hidden and returns the updated task
+func (tm *TaskManager) ToggleCollapsed(id string) (Task, bool) {
+	for i, task := range tm.Tasks {
+		if task.ID == id {
+			tm.Tasks[i].Collapsed = !task.Collapsed
+			return tm.Tasks[i], true
+		}
+	}
+	return Task{}, false
+}
+
+// TaskPath returns the chain of tasks from the top-level ancestor down to the given task
+func (tm *TaskManager) TaskPath(id string) []Task {
+	path := make([]Task, 0)
+	seen := make(map[string]bool)
+
+	for id != "" && !seen[id] {
+		seen[id] = true
+		task, found := tm.GetTask(id)
+		if !found {
+			break
+		}
+		path = append([]Task{task}, path...)
+		id = task.ParentID
+	}
+	return path
+}
+
+// EffectiveProject returns the project of a task, inherited from its nearest ancestor if unset
+func (tm *TaskManager) EffectiveProject(id string) string {
+	path := tm.TaskPath(id)
+	for i := len(path) - 1; i >= 0; i-- {
+		if path[i].Project != "" {
+			return path[i].Project
+		}
+	}
+	return ""
+}
+
+// EffectiveDurations returns the timer durations set for a task, each inherited from its nearest
+// ancestor if unset; zero durations are left to the settings
+func (tm *TaskManager) EffectiveDurations(id string) TaskDurations {
+	var durations TaskDurations
+	path := tm.TaskPath(id)
+	for i := len(path) - 1; i >= 0; i-- {
+		if path[i].Durations == nil {
+			continue
+		}
+		if durations.Pomodoro == 0 {
+			durations.Pomodoro = path[i].Durations.Pomodoro
+		}
+		if durations.ShortBreak == 0 {
+			durations.ShortBreak = path[i].Durations.ShortBreak
+		}
+		if durations.LongBreak == 0 {
+			durations.LongBreak = path[i].Durations.LongBreak
+		}
+	}
+	return durations
+}
+
+// EffectiveTags returns the tags of a task together with those inherited from its ancestors
+func (tm *TaskManager) EffectiveTags(id string) []string {
+	tags := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, task := range tm.TaskPath(id) {
+		for _, tag := range task.Tags {
+			if !seen[strings.ToLower(tag)] {
+				seen[strings.ToLower(tag)] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+	return tags
+}
+
+// VisibleTasks returns the tasks to display in depth-first tree order,
+// skipping subtasks of collapsed tasks and honouring ShowCompleted.
+// While a filter is active, matching tasks are shown together with their
+// ancestors for context, regardless of collapsed state.
+func (tm *TaskManager) VisibleTasks() []TaskNode {
+	if !tm.Filter.Empty() {
+		return tm.filteredTree()
+	}
+
+	nodes := make([]TaskNode, 0, len(tm.Tasks))
+	for _, task := range tm.Tasks {
+		if tm.isRoot(task) {
+			nodes = tm.appendVisible(nodes, task, 0, make(map[string]bool))
+		}
+	}
+	return nodes
+}
+
+// appendVisible appends a task and its visible subtasks to nodes
+func (tm *TaskManager) appendVisible(nodes []TaskNode, task Task, depth int, seen map[string]bool) []TaskNode {
+	if seen[task.ID] || !tm.isShown(task) {
+		return nodes
+	}
+	seen[task.ID] = true
+
+	children := tm.listedChildren(task.ID)
+	nodes = append(nodes, TaskNode{
+		Task:        task,
+		Depth:       depth,
+		HasChildren: len(children) > 0,
+		Totals:      tm.Totals(task.ID),
+	})
+
+	if task.Collapsed {
+		return nodes
+	}
+	for _, child := range children {
+		nodes = tm.appendVisible(nodes, child, depth+1, seen)
+	}
+	return nodes
+}
+
+// filteredTree returns the tasks matching the active filter along with their ancestors
+func (tm *TaskManager) filteredTree() []TaskNode {
+	// Mark every matching task and all of its ancestors
+	included := make(map[string]bool)
+	for _, task := range tm.Tasks {
+		if tm.isShown(task) {
+			for _, ancestor := range tm.TaskPath(task.ID) {
+				included[ancestor.ID] = true
+			}
+		}
+	}
+
+	nodes := make([]TaskNode, 0, len(included))
+	var walk func(task Task, depth int)
+	seen := make(map[string]bool)
+	walk = func(task Task, depth int) {
+		if seen[task.ID] || !included[task.ID] {
+			return
+		}
+		seen[task.ID] = true
+
+		children := tm.listedChildren(task.ID)
+		nodes = append(nodes, TaskNode{
+			Task:        task,
+			Depth:       depth,
+			HasChildren: len(children) > 0,
+			Totals:      tm.Totals(task.ID),
+		})
+		for _, child := range children {
+			walk(child, depth+1)
+		}
+	}
+
+	for _, task := range tm.Tasks {
+		if tm.isRoot(task) {
+			walk(task, 0)
+		}
+	}
+	return nodes
+}
+
+// listedChildren returns the direct subtasks of a task that are neither archived nor trashed
+func (tm *TaskManager) listedChildren(id string) []Task {
+	children := make([]Task, 0)
+	for _, child := range tm.Children(id) {
+		if child.IsListed() {
+			children = append(children, child)
+		}
+	}
+	return children
+}
+
+// isRoot reports whether a task is top-level, treating tasks with a missing parent as top-level
+func (tm *TaskManager) isRoot(task Task) bool {
+	if task.ParentID == "" {
+		return true
+	}
+	_, found := tm.GetTask(task.ParentID)
+	return !found
+}
+
+// descendantIDs returns the IDs of all subtasks of a task, at any depth
+func (tm *TaskManager) descendantIDs(id string) []string {
+	ids := make([]string, 0)
+	seen := map[string]bool{id: true}
+
+	var walk func(id string)
+	walk = func(id string) {
+		for _, child := range tm.Children(id) {
+			if seen[child.ID] {
+				continue
+			}
+			seen[child.ID] = true
+			ids = append(ids, child.ID)
+			walk(child.ID)
+		}
+	}
+	walk(id)
+	return ids
+}
+
+// SetParent moves a task under another task, or to the top level if parentID is empty, and
+// returns the updated task. It refuses moves under the task itself or one of its subtasks.
+func (tm *TaskManager) SetParent(id, parentID string) (Task, bool) {
+	if parentID != "" {
+		if _, found := tm.GetTask(parentID); !found || tm.createsCycle(id, parentID) {
+			return Task{}, false
+		}
+	}
+	for i, task := range tm.Tasks {
+		if task.ID == id {
+			tm.Tasks[i].ParentID = parentID
+			return tm.Tasks[i], true
+		}
+	}
+	return Task{}, false
+}
+
+// createsCycle reports whether making parentID the parent of a task would make the task its own ancestor
+func (tm *TaskManager) createsCycle(id, parentID string) bool {
+	seen := make(map[string]bool)
+	for parentID != "" && !seen[parentID] {
+		if parentID == id {
+			return true
+		}
+		seen[parentID] = true
+		parent, found := tm.GetTask(parentID)
+		if !found {
+			return false
+		}
+		parentID = parent.ParentID
+	}
+	return false
+}
+
+// breakParentCycles moves tasks whose parents lead back to themselves, as a hand-edited or
+// imported file may have, to the top level
+func (tm *TaskManager) breakParentCycles() {
+	for i, task := range tm.Tasks {
+		if task.ParentID != "" && tm.createsCycle(task.ID, task.ParentID) {
+			tm.Tasks[i].ParentID = ""
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTotals(t *testing.T) {
+	trashedAt := time.Date(2026, 10, 18, 0, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name          string
+		tasks         []Task
+		wantPlanned   int
+		wantPomodoros float64
+	}{
+		{
+			name:          "single task",
+			tasks:         []Task{{ID: "a", PlannedPomodoros: 4, CompletedPomodoros: 1, PomodoroFraction: 0.5}},
+			wantPlanned:   4,
+			wantPomodoros: 1.5,
+		},
+		{
+			name: "subtasks at any depth",
+			tasks: []Task{
+				{ID: "a", PlannedPomodoros: 1},
+				{ID: "b", ParentID: "a", PlannedPomodoros: 2, CompletedPomodoros: 1},
+				{ID: "c", ParentID: "b", PlannedPomodoros: 3, PomodoroFraction: 0.25},
+				{ID: "d", PlannedPomodoros: 8},
+			},
+			wantPlanned:   6,
+			wantPomodoros: 1.25,
+		},
+		{
+			name: "trashed subtasks don't count",
+			tasks: []Task{
+				{ID: "a", PlannedPomodoros: 1},
+				{ID: "b", ParentID: "a", PlannedPomodoros: 2, TrashedAt: &trashedAt},
+			},
+			wantPlanned: 1,
+		},
+		{
+			name: "parent cycle",
+			tasks: []Task{
+				{ID: "a", ParentID: "b", PlannedPomodoros: 1},
+				{ID: "b", ParentID: "a", PlannedPomodoros: 2},
+			},
+			wantPlanned: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Tasks are set directly so that cycles aren't repaired as on load
+			tm := NewTaskManager()
+			tm.Tasks = tt.tasks
+			totals := tm.Totals("a")
+			if totals.PlannedPomodoros != tt.wantPlanned || totals.Pomodoros() != tt.wantPomodoros {
+				t.Errorf("Totals() = %d planned, %v done, want %d, %v", totals.PlannedPomodoros, totals.Pomodoros(), tt.wantPlanned, tt.wantPomodoros)
+			}
+		})
+	}
+}
+
+func TestSetParent(t *testing.T) {
+	tasks := func() []Task {
+		return []Task{{ID: "a"}, {ID: "b", ParentID: "a"}, {ID: "c", ParentID: "b"}, {ID: "d"}}
+	}
+
+	tests := []struct {
+		name     string
+		id       string
+		parentID string
+		want     bool
+	}{
+		{"under another task", "d", "c", true},
+		{"to the top level", "c", "", true},
+		{"under a sibling's subtask", "b", "d", true},
+		{"under itself", "a", "a", false},
+		{"under its child", "a", "b", false},
+		{"under a deeper subtask", "a", "c", false},
+		{"under a missing task", "a", "x", false},
+		{"missing task", "x", "a", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := NewTaskManager()
+			tm.LoadTasks(tasks())
+			task, ok := tm.SetParent(tt.id, tt.parentID)
+			if ok != tt.want {
+				t.Fatalf("SetParent(%q, %q) = %v, want %v", tt.id, tt.parentID, ok, tt.want)
+			}
+			if ok && task.ParentID != tt.parentID {
+				t.Errorf("parent = %q, want %q", task.ParentID, tt.parentID)
+			}
+		})
+	}
+}
+
+func TestLoadTasksBreaksParentCycles(t *testing.T) {
+	tm := NewTaskManager()
+	tm.LoadTasks([]Task{{ID: "a", ParentID: "c"}, {ID: "b", ParentID: "a"}, {ID: "c", ParentID: "b"}, {ID: "d", ParentID: "a"}})
+
+	roots := 0
+	for _, task := range tm.Tasks {
+		if task.ParentID == "" {
+			roots++
+		}
+		if task.ParentID != "" && tm.createsCycle(task.ID, task.ParentID) {
+			t.Errorf("task %s is still its own ancestor", task.ID)
+		}
+	}
+	if roots != 1 {
+		t.Errorf("got %d top-level tasks, want 1", roots)
+	}
+	if got := len(tm.VisibleTasks()); got != 4 {
+		t.Errorf("got %d visible tasks, want 4", got)
+	}
+}
@@ -40,10 +40,12 @@ type Timer struct {
 	State TimerState
 	// Current mode (focus, short break, long break)
 	Mode TimerMode
-	// Time remaining in the current timer
+	// Time remaining in the current timer (negative once a Flowtime focus runs past the pomodoro duration)
 	Remaining time.Duration
-	// When the timer was started
+	// When the timer was started (shifted forward on resume to exclude pauses)
 	StartTime time.Time
+	// When the current session was first started, including any pauses
+	SessionStart time.Time
 	// The original duration for this timer
 	Duration time.Duration
 	// Number of completed pomodoros in the current cycle
@@ -56,6 +58,24 @@ type Timer struct {
 	TaskManager *TaskManager
 	// Settings for timer durations
 	Settings *Settings
+	// History that finished, stopped and skipped sessions are recorded to (nil to disable)
+	History *History
+	// Break earned by the last Flowtime focus period (0 if none is pending)
+	FlowtimeBreak time.Duration
+	// Activity suggested for the current or last break
+	BreakActivity string
+	// Times skipping or cutting short the current break was attempted in strict mode
+	SkipAttempts int
+	// When the next pomodoro starts automatically (zero if none is counting down)
+	AutoStartAt time.Time
+	// Whether the task queue ran out, which stops pomodoros starting automatically until a task is chosen
+	QueueEnded bool
+
+	// Time spent on each task so far in the current focus period, in the order first worked on
+	segments []TaskSegment
+	// Focus time, excluding pauses, and wall-clock time at which the current task was set
+	segmentStart time.Duration
+	segmentAt    time.Time
 }
 
 // NewTimer creates a new timer with default settings
@@ -83,17 +103,54 @@ func (t *Timer) SetSettings(settings *Settings) {
 	t.updateDurationFromSettings()
 }
 
-// updateDurationFromSettings updates the timer duration based on current mode and settings
-func (t *Timer) updateDurationFromSettings() {
-	// Update the mode duration
-	switch t.Mode {
-	case FocusMode:
-		t.Duration = t.Settings.GetPomodoroDuration()
+// SetHistory sets the history that sessions are recorded to
+func (t *Timer) SetHistory(history *History) {
+	t.History = history
+}
+
+// modeDuration returns the duration of a mode, taken from the current task if it overrides the settings
+func (t *Timer) modeDuration(mode TimerMode) time.Duration {
+	var overrides TaskDurations
+	if t.CurrentTaskID != "" && t.TaskManager != nil {
+		overrides = t.TaskManager.EffectiveDurations(t.CurrentTaskID)
+	}
+
+	switch mode {
 	case ShortBreakMode:
-		t.Duration = t.Settings.GetShortBreakDuration()
+		if t.FlowtimeBreak > 0 {
+			return t.FlowtimeBreak
+		}
+		if overrides.ShortBreak > 0 {
+			return time.Duration(overrides.ShortBreak) * time.Minute
+		}
+		return t.Settings.GetShortBreakDuration()
 	case LongBreakMode:
-		t.Duration = t.Settings.GetLongBreakDuration()
+		long := t.Settings.GetLongBreakDuration()
+		if overrides.LongBreak > 0 {
+			long = time.Duration(overrides.LongBreak) * time.Minute
+		}
+		// A long break is never shorter than the break a Flowtime focus earned
+		if t.FlowtimeBreak > long {
+			return t.FlowtimeBreak
+		}
+		return long
+	default:
+		if overrides.Pomodoro > 0 {
+			return time.Duration(overrides.Pomodoro) * time.Minute
+		}
+		return t.Settings.GetPomodoroDuration()
 	}
+}
+
+// updateDurationFromSettings updates the timer duration based on current mode, settings and task
+func (t *Timer) updateDurationFromSettings() {
+	// A break under way in strict mode keeps its length
+	if t.BreakLocked() && t.State != TimerStopped {
+		return
+	}
+
+	// Update the mode duration
+	t.Duration = t.modeDuration(t.Mode)
 
 	// Only reset the remaining time if the timer is stopped
 	if t.State == TimerStopped {
@@ -111,38 +168,32 @@ func (t *Timer) Start() {
 	}
 
 	// Otherwise, start a fresh timer
+	t.AutoStartAt = time.Time{}
+	t.segments = nil
+	t.segmentStart = 0
+	t.updateDurationFromSettings()
 	t.State = TimerRunning
 	t.StartTime = time.Now()
-	t.updateDurationFromSettings()
+	t.SessionStart = t.StartTime
+	t.segmentAt = t.StartTime
 }
 
-// Stop stops the timer
-func (t *Timer) Stop() {
-	// Only handle task updates if we were in focus mode and timer was running
-	if t.State == TimerRunning && t.Mode == FocusMode {
-		// Calculate how much of the pomodoro was completed
-		elapsed := time.Since(t.StartTime)
-		percentComplete := (float64(elapsed) / float64(t.Duration)) * 100
-
-		// If at least 50% of the pomodoro was completed, count it as done
-		if percentComplete >= 50 {
-			t.CompletedPomodoros++
+// Reset resets the timer to its initial state for the current mode. In strict mode a break
+// under way is left running.
+func (t *Timer) Reset() {
+	if t.BreakLocked() && t.State != TimerStopped {
+		return
+	}
+	// Resetting also cancels a pomodoro counting down to start
+	t.AutoStartAt = time.Time{}
 
-			// Update current task if one is set
-			if t.CurrentTaskID != "" && t.TaskManager != nil {
-				t.TaskManager.AddCompletedPomodoro(t.CurrentTaskID)
-				t.TaskManager.AddTimeSpent(t.CurrentTaskID, elapsed) // Add actual time spent
-			}
+	// Record the abandoned session, if one was in progress
+	if t.State == TimerRunning || t.State == TimerPaused {
+		if elapsed := t.elapsed(); elapsed > 0 {
+			t.recordSession(elapsed, false, false)
 		}
 	}
 
-	t.State = TimerStopped
-	// Reset to initial duration based on current mode
-	t.updateDurationFromSettings()
-}
-
-// Reset resets the timer to its initial state for the current mode
-func (t *Timer) Reset() {
 	t.State = TimerStopped
 	t.updateDurationFromSettings()
 }
@@ -154,12 +205,43 @@ func (t *Timer) Pause() {
 		// Calculate remaining time
 		elapsed := time.Since(t.StartTime)
 		t.Remaining = t.Duration - elapsed
-		if t.Remaining < 0 {
+		// A Flowtime focus keeps counting past the pomodoro duration
+		if t.Remaining < 0 && !t.IsFlowing() {
 			t.Remaining = 0
 		}
 	}
 }
 
+// IsFlowing reports whether the timer is in a Flowtime focus period, counting up until stopped
+func (t *Timer) IsFlowing() bool {
+	return t.Mode == FocusMode && t.Settings != nil && t.Settings.Flowtime
+}
+
+// FinishFlow ends a Flowtime focus period and proposes a break proportional to its length, or a
+// long break at least as long once a cycle of pomodoros is complete, moving on as when a pomodoro
+// ends. It returns false if no Flowtime focus was under way.
+func (t *Timer) FinishFlow() bool {
+	if !t.IsFlowing() || (t.State != TimerRunning && t.State != TimerPaused) {
+		return false
+	}
+
+	elapsed := t.elapsed()
+	// Like a stopped pomodoro, a flow counts as one if it lasted at least half of one,
+	// but all of its time is credited to the task
+	counted := elapsed >= t.Duration/2
+	t.recordSession(elapsed, counted, false)
+
+	if counted {
+		t.CompletedPomodoros++
+	}
+	t.creditTasks(elapsed, counted)
+
+	t.State = TimerStopped
+	t.FlowtimeBreak = t.Settings.GetFlowtimeBreak(elapsed)
+	t.moveOn(counted)
+	return true
+}
+
 // Resume resumes the timer from a paused state
 func (t *Timer) Resume() {
 	if t.State == TimerPaused {
@@ -168,13 +250,27 @@ func (t *Timer) Resume() {
 	}
 }
 
-// SetCurrentTask sets the current task
+// SetCurrentTask sets the current task. Switching tasks during a focus period splits its time
+// between the tasks.
 func (t *Timer) SetCurrentTask(taskID string) {
+	if taskID != t.CurrentTaskID && t.Mode == FocusMode && (t.State == TimerRunning || t.State == TimerPaused) {
+		t.closeSegment(t.elapsed())
+	}
 	t.CurrentTaskID = taskID
+	t.QueueEnded = false
+	// Show the task's own durations, if it has any, until the timer is started
+	if t.State == TimerStopped {
+		t.updateDurationFromSettings()
+	}
 }
 
 // Update updates the timer's state and returns true if the timer completed
 func (t *Timer) Update() bool {
+	// Start a pomodoro whose countdown has run out
+	if t.State == TimerStopped && !t.AutoStartAt.IsZero() && !time.Now().Before(t.AutoStartAt) {
+		t.Start()
+	}
+
 	if t.State != TimerRunning {
 		return false
 	}
@@ -183,84 +279,309 @@ func (t *Timer) Update() bool {
 	elapsed := time.Since(t.StartTime)
 	t.Remaining = t.Duration - elapsed
 
+	// A Flowtime focus only ends when stopped with FinishFlow
+	if t.IsFlowing() {
+		return false
+	}
+
 	// Check if timer has finished
 	if t.Remaining <= 0 {
 		t.Remaining = 0
 		t.State = TimerStopped
 
+		// Record the finished session before moving on
+		t.recordSession(t.Duration, true, false)
+
 		// If we were in focus mode, increment completed pomodoros
 		if t.Mode == FocusMode {
 			t.CompletedPomodoros++
 
-			// Update current task if one is set
-			if t.CurrentTaskID != "" && t.TaskManager != nil {
-				t.TaskManager.AddCompletedPomodoro(t.CurrentTaskID)
-				t.TaskManager.AddTimeSpent(t.CurrentTaskID, t.Duration)
+			// Credit the tasks worked on
+			t.creditTasks(t.Duration, true)
+		}
+
+		// Move on to the next timer mode
+		t.moveOn(true)
+
+		return true // Timer completed
+	}
+
+	return false // Timer still running
+}
+
+// moveOn advances to the next timer mode once a focus period or break has ended. Breaks start
+// right away if they start automatically or strict mode is on, and after a break the next
+// pomodoro is counted down to if pomodoros start automatically. The task queue moves on from a
+// task that was completed or reached its plan in the meantime.
+func (t *Timer) moveOn(counted bool) {
+	// Advance to the next timer mode
+	t.advanceTimerMode(counted)
+
+	// Auto-start breaks if enabled in settings, or enforced by strict mode, and we're in a break mode
+	if t.Settings != nil && (t.Settings.AutoStartBreaks || t.Settings.StrictMode) &&
+		(t.Mode == ShortBreakMode || t.Mode == LongBreakMode) {
+		t.Start()
+	}
+
+	// Move on from a task that was completed or reached its plan in the meantime
+	t.AdvanceQueue()
+
+	// After a break, count down to the next pomodoro, unless the queue has run out of tasks
+	if t.Mode == FocusMode && !t.QueueEnded {
+		if t.Settings != nil && t.Settings.AutoStartPomodoros {
+			t.AutoStartAt = time.Now().Add(t.Settings.GetAutoStartGrace())
+			if t.Settings.AutoStartGrace == 0 {
+				t.Start()
 			}
 		}
+	}
+}
 
-		// Advance to the next timer mode
-		t.advanceTimerMode()
+// closeSegment ends the stretch of the focus period spent on the current task, given the focus
+// time so far
+func (t *Timer) closeSegment(focused time.Duration) {
+	spent := focused - t.segmentStart
+	start := t.segmentAt
+	t.segmentStart = focused
+	t.segmentAt = time.Now()
+	if spent <= 0 {
+		return
+	}
+
+	if last := len(t.segments) - 1; last >= 0 && t.segments[last].TaskID == t.CurrentTaskID {
+		t.segments[last].Duration += spent
+		t.segments[last].End = t.segmentAt
+		return
+	}
+	t.segments = append(t.segments, TaskSegment{TaskID: t.CurrentTaskID, Start: start, End: t.segmentAt, Duration: spent})
+}
 
-		// Auto-start breaks if enabled in settings and we're in a break mode
-		if t.Settings != nil && t.Settings.AutoStartBreaks &&
-			(t.Mode == ShortBreakMode || t.Mode == LongBreakMode) {
-			t.Start()
+// attributePomodoro shares out the pomodoro of the focus period between its stretches according
+// to the attribution rule, or credits none if it didn't count. Time without a task selected is
+// not credited to anyone.
+func (t *Timer) attributePomodoro(counted bool) {
+	var tasked time.Duration
+	totals := make(map[string]time.Duration)
+	for i, segment := range t.segments {
+		t.segments[i].Pomodoros = 0
+		if segment.TaskID != "" {
+			tasked += segment.Duration
+			totals[segment.TaskID] += segment.Duration
 		}
+	}
+	if !counted || tasked == 0 {
+		return
+	}
 
-		return true // Timer completed
+	// The whole pomodoro goes to the last stretch on the task that earned it
+	whole := ""
+	switch t.Settings.GetPomodoroAttribution() {
+	case AttributeLast:
+		for _, segment := range t.segments {
+			if segment.TaskID != "" {
+				whole = segment.TaskID
+			}
+		}
+	case AttributeMajority:
+		whole = longestTask(t.segments, totals)
+	default:
+		for i, segment := range t.segments {
+			if segment.TaskID != "" {
+				t.segments[i].Pomodoros = float64(segment.Duration) / float64(tasked)
+			}
+		}
+		return
 	}
 
-	return false // Timer still running
+	for i := len(t.segments) - 1; i >= 0; i-- {
+		if t.segments[i].TaskID == whole {
+			t.segments[i].Pomodoros = 1
+			return
+		}
+	}
+}
+
+// longestTask returns the task most of the time of the stretches went to, the first worked on
+// in a tie, given the total time per task
+func longestTask(segments []TaskSegment, totals map[string]time.Duration) string {
+	longest := ""
+	for _, segment := range segments {
+		if segment.TaskID != "" && (longest == "" || totals[segment.TaskID] > totals[longest]) {
+			longest = segment.TaskID
+		}
+	}
+	return longest
+}
+
+// creditTasks credits the tasks worked on during a focus period that ran for the given time with
+// the time spent on each and, if it counted, the pomodoro according to the attribution rule
+func (t *Timer) creditTasks(ran time.Duration, counted bool) {
+	t.closeSegment(ran)
+	t.attributePomodoro(counted)
+	segments := t.segments
+	t.segments = nil
+	t.segmentStart = 0
+	if t.TaskManager == nil {
+		return
+	}
+
+	shares := make(map[string]float64)
+	order := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		if segment.TaskID == "" {
+			continue
+		}
+		t.TaskManager.AddTimeSpent(segment.TaskID, segment.Duration)
+		if _, seen := shares[segment.TaskID]; !seen {
+			order = append(order, segment.TaskID)
+		}
+		shares[segment.TaskID] += segment.Pomodoros
+	}
+	for _, taskID := range order {
+		if shares[taskID] > 0 {
+			t.TaskManager.AddPomodoroShare(taskID, shares[taskID])
+		}
+	}
+}
+
+// sessionSegments returns the stretches of the focus period with a snapshot of their tasks and
+// their shares of the pomodoro, and the ID of the task most of it went to. There are no
+// segments unless tasks were switched.
+func (t *Timer) sessionSegments(ran time.Duration, counted bool) ([]TaskSegment, string) {
+	t.closeSegment(ran)
+	t.attributePomodoro(counted)
+
+	totals := make(map[string]time.Duration)
+	for _, segment := range t.segments {
+		totals[segment.TaskID] += segment.Duration
+	}
+	mainID := longestTask(t.segments, totals)
+	if mainID == "" {
+		mainID = t.CurrentTaskID
+	}
+	if len(t.segments) < 2 {
+		return nil, mainID
+	}
+
+	segments := make([]TaskSegment, len(t.segments))
+	for i, segment := range t.segments {
+		segments[i] = segment
+		if task, found := t.TaskManager.GetTask(segment.TaskID); found {
+			segments[i].TaskDescription = task.Description
+			segments[i].Project = t.TaskManager.EffectiveProject(task.ID)
+			segments[i].Tags = t.TaskManager.EffectiveTags(task.ID)
+		}
+	}
+	return segments, mainID
+}
+
+// AdvanceQueue selects the next task in planned order once the current task is completed or its
+// planned pomodoros are done, or no task at the end of the queue. It returns true if the current
+// task changed.
+func (t *Timer) AdvanceQueue() bool {
+	if t.CurrentTaskID == "" || t.TaskManager == nil {
+		return false
+	}
+	if current, found := t.TaskManager.GetTask(t.CurrentTaskID); found && !current.queueDone() {
+		return false
+	}
+
+	next, ok := t.TaskManager.NextQueuedTask(t.CurrentTaskID)
+	t.SetCurrentTask(next.ID)
+	t.QueueEnded = !ok
+	return true
 }
 
-// advanceTimerMode moves to the next timer mode based on the completed pomodoros
-func (t *Timer) advanceTimerMode() {
+// AutoStartIn returns how long until the next pomodoro starts automatically, and false if none is counting down
+func (t *Timer) AutoStartIn() (time.Duration, bool) {
+	if t.State != TimerStopped || t.AutoStartAt.IsZero() {
+		return 0, false
+	}
+	return time.Until(t.AutoStartAt), true
+}
+
+// advanceTimerMode moves to the next timer mode based on the completed pomodoros. Only a counted
+// pomodoro that completes a cycle is followed by a long break.
+func (t *Timer) advanceTimerMode(counted bool) {
 	switch t.Mode {
 	case FocusMode:
 		// After focus mode, decide if we need a short or long break
-		if t.CompletedPomodoros%t.PomodorosPerCycle == 0 {
+		if counted && t.CompletedPomodoros%t.PomodorosPerCycle == 0 {
 			// Long break after completing a cycle
 			t.Mode = LongBreakMode
-			t.Duration = t.Settings.GetLongBreakDuration()
 		} else {
 			// Short break after individual pomodoro
 			t.Mode = ShortBreakMode
-			t.Duration = t.Settings.GetShortBreakDuration()
 		}
+		t.BreakActivity = PickBreakActivity(t.Settings.BreakActivities, t.BreakActivity)
 	case ShortBreakMode, LongBreakMode:
 		// After any break, go back to focus mode
 		t.Mode = FocusMode
-		t.Duration = t.Settings.GetPomodoroDuration()
+		t.FlowtimeBreak = 0
 	}
+	t.Duration = t.modeDuration(t.Mode)
 
 	t.Remaining = t.Duration
 }
 
-// FormatTime formats the remaining time as mm:ss
+// FormatTime formats the remaining time as mm:ss, or the time counted up during a Flowtime focus
 func (t *Timer) FormatTime() string {
-	minutes := int(t.Remaining.Minutes())
-	seconds := int(t.Remaining.Seconds()) % 60
+	shown := t.Remaining
+	if t.IsFlowing() {
+		shown = t.Duration - t.Remaining
+	}
+	minutes := int(shown.Minutes())
+	seconds := int(shown.Seconds()) % 60
 	return fmt.Sprintf("%02d:%02d", minutes, seconds)
 }
 
-// ProgressPercentage returns the percentage of time elapsed
+// ProgressPercentage returns the percentage of time elapsed, capped at 100 for a Flowtime focus
+// running past the pomodoro duration
 func (t *Timer) ProgressPercentage() float64 {
 	if t.Duration <= 0 {
 		return 0
 	}
+	if t.Remaining < 0 {
+		return 100
+	}
 	return 100.0 * (1.0 - float64(t.Remaining)/float64(t.Duration))
 }
 
-// SkipBreak skips the current break and advances to focus mode
+// BreakLocked reports whether the timer is in a break that strict mode doesn't let be skipped,
+// so the next focus period can't be started until it ends
+func (t *Timer) BreakLocked() bool {
+	return t.Settings != nil && t.Settings.StrictMode && (t.Mode == ShortBreakMode || t.Mode == LongBreakMode)
+}
+
+// NoteSkipAttempt counts an attempt to skip or cut short a break in strict mode, recorded
+// with the break in the history
+func (t *Timer) NoteSkipAttempt() {
+	t.SkipAttempts++
+}
+
+// SkipBreak skips the current break and advances to focus mode. In strict mode the attempt
+// is counted instead.
 func (t *Timer) SkipBreak() {
+	if t.BreakLocked() {
+		t.NoteSkipAttempt()
+		return
+	}
+
 	// Only allow skipping if we're in a break mode
 	if t.Mode == ShortBreakMode || t.Mode == LongBreakMode {
-		// Stop the current timer if it's running
-		t.Stop()
+		// Record the skipped break, whether or not it was started
+		var elapsed time.Duration
+		if t.State == TimerRunning || t.State == TimerPaused {
+			elapsed = t.elapsed()
+		}
+		t.recordSession(elapsed, false, true)
+
+		// Stop the current timer
+		t.State = TimerStopped
 
 		// Set to focus mode
 		t.Mode = FocusMode
+		t.FlowtimeBreak = 0
 
 		// Update the duration based on the new mode
 		t.updateDurationFromSettings()
@@ -269,3 +590,58 @@ func (t *Timer) SkipBreak() {
 		t.Reset()
 	}
 }
+
+// elapsed returns how long the current session has been running, excluding pauses
+func (t *Timer) elapsed() time.Duration {
+	switch t.State {
+	case TimerRunning:
+		return time.Since(t.StartTime)
+	case TimerPaused:
+		return t.Duration - t.Remaining
+	default:
+		return 0
+	}
+}
+
+// recordSession adds the current session to the history, if one is set
+func (t *Timer) recordSession(ran time.Duration, completed, skipped bool) {
+	if t.History == nil {
+		return
+	}
+
+	end := time.Now()
+	start := t.SessionStart
+	if start.IsZero() {
+		start = end.Add(-ran)
+	}
+
+	session := Session{
+		Mode:      t.Mode,
+		Start:     start,
+		End:       end,
+		Planned:   t.Duration,
+		Duration:  ran,
+		Completed: completed,
+		Skipped:   skipped,
+		Flowtime:  t.IsFlowing() || t.FlowtimeBreak > 0,
+	}
+	if session.IsBreak() {
+		session.SkipAttempts = t.SkipAttempts
+		t.SkipAttempts = 0
+	}
+
+	// Attach a snapshot of the task for focus sessions, and of each task if tasks were switched
+	if t.Mode == FocusMode && t.TaskManager != nil {
+		var taskID string
+		session.Segments, taskID = t.sessionSegments(ran, completed)
+		if task, found := t.TaskManager.GetTask(taskID); found {
+			session.TaskID = task.ID
+			session.TaskDescription = task.Description
+			session.Project = t.TaskManager.EffectiveProject(task.ID)
+			session.Tags = t.TaskManager.EffectiveTags(task.ID)
+		}
+	}
+
+	t.History.Record(session)
+	t.SessionStart = time.Time{}
+}
@@ -0,0 +1,127 @@
+package model
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestAttributePomodoro(t *testing.T) {
+	segments := func(stretches ...interface{}) []TaskSegment {
+		result := make([]TaskSegment, 0, len(stretches)/2)
+		for i := 0; i < len(stretches); i += 2 {
+			result = append(result, TaskSegment{TaskID: stretches[i].(string), Duration: time.Duration(stretches[i+1].(int)) * time.Minute})
+		}
+		return result
+	}
+
+	tests := []struct {
+		name     string
+		rule     string
+		segments []TaskSegment
+		counted  bool
+		want     []float64
+	}{
+		{"split", AttributeSplit, segments("a", 15, "b", 10), true, []float64{0.6, 0.4}},
+		{"split is the default", "", segments("a", 15, "b", 10), true, []float64{0.6, 0.4}},
+		{"split skips time without a task", AttributeSplit, segments("a", 10, "", 5, "b", 10), true, []float64{0.5, 0, 0.5}},
+		{"split with a task twice", AttributeSplit, segments("a", 5, "b", 15, "a", 5), true, []float64{0.2, 0.6, 0.2}},
+		{"majority", AttributeMajority, segments("a", 5, "b", 15, "a", 5), true, []float64{0, 1, 0}},
+		{"majority adds up stretches", AttributeMajority, segments("a", 8, "b", 10, "a", 7), true, []float64{0, 0, 1}},
+		{"majority tie goes to the first task", AttributeMajority, segments("a", 10, "b", 10), true, []float64{1, 0}},
+		{"last", AttributeLast, segments("a", 20, "b", 5), true, []float64{0, 1}},
+		{"last ignores time without a task", AttributeLast, segments("a", 20, "", 5), true, []float64{1, 0}},
+		{"not counted", AttributeSplit, segments("a", 15, "b", 10), false, []float64{0, 0}},
+		{"no task", AttributeSplit, segments("", 25), true, []float64{0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timer := &Timer{Settings: &Settings{PomodoroAttribution: tt.rule}, segments: tt.segments}
+			timer.attributePomodoro(tt.counted)
+			for i, segment := range timer.segments {
+				if math.Abs(segment.Pomodoros-tt.want[i]) > 1e-9 {
+					t.Errorf("segment %d (%s) got %v, want %v", i, segment.TaskID, segment.Pomodoros, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestTaskAddPomodoroShare(t *testing.T) {
+	tests := []struct {
+		name          string
+		shares        []float64
+		wantCompleted int
+		wantFraction  float64
+	}{
+		{"half", []float64{0.5}, 0, 0.5},
+		{"halves add up", []float64{0.5, 0.5}, 1, 0},
+		{"thirds add up despite rounding", []float64{1.0 / 3, 1.0 / 3, 1.0 / 3}, 1, 0},
+		{"whole and a bit", []float64{1, 0.25}, 1, 0.25},
+		{"past a whole", []float64{0.6, 0.6}, 1, 0.2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := Task{PlannedPomodoros: 4}
+			for _, share := range tt.shares {
+				task.AddPomodoroShare(share)
+			}
+			if task.CompletedPomodoros != tt.wantCompleted || math.Abs(task.PomodoroFraction-tt.wantFraction) > 1e-9 {
+				t.Errorf("got %d + %v, want %d + %v", task.CompletedPomodoros, task.PomodoroFraction, tt.wantCompleted, tt.wantFraction)
+			}
+		})
+	}
+}
+
+func TestFinishFlow(t *testing.T) {
+	tests := []struct {
+		name          string
+		completed     int
+		focused       time.Duration
+		wantMode      TimerMode
+		wantDuration  time.Duration
+		wantCompleted int
+		wantNextTask  bool
+	}{
+		{"short break earned by the flow", 0, 50 * time.Minute, ShortBreakMode, 10 * time.Minute, 1, true},
+		{"long break after a cycle", 3, 50 * time.Minute, LongBreakMode, 30 * time.Minute, 4, true},
+		{"long flow lengthens the long break", 3, 200 * time.Minute, LongBreakMode, 40 * time.Minute, 4, true},
+		{"short flow doesn't count", 3, 5 * time.Minute, ShortBreakMode, time.Minute, 3, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := NewTaskManager()
+			first := tm.AddTask("Write docs", 1)
+			second := tm.AddTask("Review", 1)
+
+			timer := NewTimer(tm)
+			settings := DefaultSettings()
+			settings.Flowtime = true
+			timer.SetSettings(&settings)
+			timer.CompletedPomodoros = tt.completed
+			timer.SetCurrentTask(first.ID)
+			timer.Start()
+			timer.StartTime = time.Now().Add(-tt.focused)
+
+			if !timer.FinishFlow() {
+				t.Fatal("FinishFlow() = false during a flow")
+			}
+			if timer.Mode != tt.wantMode || timer.Duration != tt.wantDuration || timer.CompletedPomodoros != tt.wantCompleted {
+				t.Errorf("got %v for %s after %d pomodoros, want %v for %s after %d",
+					timer.Mode, timer.Duration, timer.CompletedPomodoros, tt.wantMode, tt.wantDuration, tt.wantCompleted)
+			}
+
+			// The queue moves on once the task's plan is done, as after a pomodoro
+			wantTask := first.ID
+			if tt.wantNextTask {
+				wantTask = second.ID
+			}
+			if timer.CurrentTaskID != wantTask {
+				t.Errorf("current task %q, want %q", timer.CurrentTaskID, wantTask)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TimesheetGroupBy selects how a timesheet groups focus sessions
+type TimesheetGroupBy string
+
+const (
+	// GroupByDay adds one row per calendar day
+	GroupByDay TimesheetGroupBy = "day"
+	// GroupByTask adds one row per task
+	GroupByTask TimesheetGroupBy = "task"
+	// GroupByProject adds one row per project
+	GroupByProject TimesheetGroupBy = "project"
+)
+
+// NoTaskName labels focus sessions that were not attributed to a task
+const NoTaskName = "(no task)"
+
+// ParseTimesheetGroupBy parses "day", "task" or "project"
+func ParseTimesheetGroupBy(value string) (TimesheetGroupBy, error) {
+	switch groupBy := TimesheetGroupBy(strings.ToLower(value)); groupBy {
+	case GroupByDay, GroupByTask, GroupByProject:
+		return groupBy, nil
+	}
+	return "", fmt.Errorf("invalid grouping %q: use day, task or project", value)
+}
+
+// TimesheetRow is a single line of a timesheet
+type TimesheetRow struct {
+	// Date (YYYY-MM-DD), task ID or project name
+	Key string
+	// Human-readable label: the date, task description or project name
+	Label string
+	// Project of the task (task grouping only)
+	Project string
+	// Status of the task: open, done, archived, trashed or deleted (task grouping only)
+	Status string
+	// Planned pomodoros of the task (task grouping only)
+	Estimate int
+	// Completed pomodoros, all focus sessions and focus time within the period. Pomodoros
+	// split between rows are shared out by the attribution rule they were recorded with.
+	Pomodoros float64
+	Sessions  int
+	FocusTime time.Duration
+}
+
+// Timesheet aggregates focus sessions within a period of whole days
+type Timesheet struct {
+	// First and last day of the period (inclusive)
+	From    time.Time
+	To      time.Time
+	GroupBy TimesheetGroupBy
+	Rows    []TimesheetRow
+	Total   TimesheetRow
+}
+
+// BuildTimesheet aggregates the focus sessions started between the from and to days (inclusive).
+// Rows are ordered by date for day grouping and by focus time, then label, otherwise, so the
+// same data always produces the same timesheet.
+func BuildTimesheet(history *History, tm *TaskManager, from, to time.Time, groupBy TimesheetGroupBy) Timesheet {
+	sheet := Timesheet{
+		From:    StartOfDay(from),
+		To:      StartOfDay(to),
+		GroupBy: groupBy,
+		Total:   TimesheetRow{Key: "total", Label: "Total"},
+	}
+
+	rows := make(map[string]*TimesheetRow)
+	for _, session := range history.SessionsBetween(sheet.From, sheet.To.AddDate(0, 0, 1)) {
+		if !session.IsFocus() {
+			continue
+		}
+
+		sheet.Total.Sessions++
+		sheet.Total.FocusTime += session.Duration
+		if session.Completed {
+			sheet.Total.Pomodoros++
+		}
+
+		// Sessions that switched tasks are split between the rows of their tasks
+		counted := make(map[string]bool)
+		for _, part := range session.Parts() {
+			key := timesheetKey(part, groupBy)
+			row, ok := rows[key]
+			if !ok {
+				row = newTimesheetRow(key, part, tm, groupBy)
+				rows[key] = row
+			}
+
+			if !counted[key] {
+				counted[key] = true
+				row.Sessions++
+			}
+			row.FocusTime += part.Duration
+			row.Pomodoros += part.Pomodoros()
+		}
+	}
+
+	for _, row := range rows {
+		sheet.Rows = append(sheet.Rows, *row)
+	}
+	sort.Slice(sheet.Rows, func(i, j int) bool {
+		a, b := sheet.Rows[i], sheet.Rows[j]
+		if groupBy != GroupByDay && a.FocusTime != b.FocusTime {
+			return a.FocusTime > b.FocusTime
+		}
+		if a.Label != b.Label {
+			return a.Label < b.Label
+		}
+		return a.Key < b.Key
+	})
+
+	return sheet
+}
+
+// timesheetKey returns the row a focus session is counted in
+func timesheetKey(session Session, groupBy TimesheetGroupBy) string {
+	switch groupBy {
+	case GroupByTask:
+		return session.TaskID
+	case GroupByProject:
+		if session.Project == "" {
+			return NoProjectName
+		}
+		return session.Project
+	default:
+		return StartOfDay(session.Start).Format("2006-01-02")
+	}
+}
+
+// newTimesheetRow creates the row for a key, filling in task details from the task manager
+func newTimesheetRow(key string, session Session, tm *TaskManager, groupBy TimesheetGroupBy) *TimesheetRow {
+	row := &TimesheetRow{Key: key, Label: key}
+	if groupBy != GroupByTask {
+		return row
+	}
+
+	if key == "" {
+		row.Label = NoTaskName
+		return row
+	}
+
+	// Fall back to the session snapshot for tasks that no longer exist
+	row.Label = session.TaskDescription
+	row.Project = session.Project
+	row.Status = "deleted"
+
+	task, found := tm.GetTask(key)
+	if !found {
+		return row
+	}
+	names := make([]string, 0)
+	for _, ancestor := range tm.TaskPath(task.ID) {
+		names = append(names, ancestor.Description)
+	}
+	row.Label = strings.Join(names, " › ")
+	row.Project = tm.EffectiveProject(task.ID)
+	row.Estimate = task.PlannedPomodoros
+	switch {
+	case task.InTrash():
+		row.Status = "trashed"
+	case task.Archived:
+		row.Status = "archived"
+	case task.Completed:
+		row.Status = "done"
+	default:
+		row.Status = "open"
+	}
+	return row
+}
@@ -0,0 +1,124 @@
+package model
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSessionParts(t *testing.T) {
+	start := time.Date(2026, 10, 18, 9, 0, 0, 0, time.Local)
+	at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+
+	tests := []struct {
+		name          string
+		session       Session
+		wantTasks     []string
+		wantPomodoros []float64
+		wantStarts    []time.Time
+	}{
+		{
+			name:          "single task",
+			session:       Session{Mode: FocusMode, TaskID: "a", Start: start, End: at(25), Duration: 25 * time.Minute, Completed: true},
+			wantTasks:     []string{"a"},
+			wantPomodoros: []float64{1},
+			wantStarts:    []time.Time{start},
+		},
+		{
+			name: "split between tasks",
+			session: Session{Mode: FocusMode, TaskID: "a", Start: start, End: at(25), Duration: 25 * time.Minute, Completed: true, Segments: []TaskSegment{
+				{TaskID: "a", Start: start, End: at(15), Duration: 15 * time.Minute, Pomodoros: 0.6},
+				{TaskID: "b", Start: at(15), End: at(25), Duration: 10 * time.Minute, Pomodoros: 0.4},
+			}},
+			wantTasks:     []string{"a", "b"},
+			wantPomodoros: []float64{0.6, 0.4},
+			wantStarts:    []time.Time{start, at(15)},
+		},
+		{
+			name: "recorded before shares were kept",
+			session: Session{Mode: FocusMode, TaskID: "b", Start: start, End: at(25), Duration: 25 * time.Minute, Completed: true, Segments: []TaskSegment{
+				{TaskID: "a", Duration: 10 * time.Minute},
+				{TaskID: "b", Duration: 15 * time.Minute},
+			}},
+			wantTasks:     []string{"a", "b"},
+			wantPomodoros: []float64{0, 1},
+			wantStarts:    []time.Time{start, start},
+		},
+		{
+			name:          "interrupted",
+			session:       Session{Mode: FocusMode, TaskID: "a", Start: start, End: at(10), Duration: 10 * time.Minute},
+			wantTasks:     []string{"a"},
+			wantPomodoros: []float64{0},
+			wantStarts:    []time.Time{start},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parts := tt.session.Parts()
+			if len(parts) != len(tt.wantTasks) {
+				t.Fatalf("got %d parts, want %d", len(parts), len(tt.wantTasks))
+			}
+			for i, part := range parts {
+				if part.TaskID != tt.wantTasks[i] || math.Abs(part.Pomodoros()-tt.wantPomodoros[i]) > 1e-9 || !part.Start.Equal(tt.wantStarts[i]) {
+					t.Errorf("part %d = %s %v from %s, want %s %v from %s", i,
+						part.TaskID, part.Pomodoros(), part.Start.Format("15:04"),
+						tt.wantTasks[i], tt.wantPomodoros[i], tt.wantStarts[i].Format("15:04"))
+				}
+			}
+		})
+	}
+}
+
+func TestBuildTimesheetSplitsSessions(t *testing.T) {
+	tm := NewTaskManager()
+	docs := tm.AddTask("Write docs", 4)
+	docs.Project = "acme"
+	tm.UpdateTask(docs)
+	review := tm.AddTask("Review", 2)
+
+	day := time.Date(2026, 10, 18, 9, 0, 0, 0, time.Local)
+	at := func(minutes int) time.Time { return day.Add(time.Duration(minutes) * time.Minute) }
+
+	history := NewHistory()
+	history.LoadSessions([]Session{
+		{ID: "1", Mode: FocusMode, TaskID: docs.ID, Project: "acme", Start: at(0), End: at(25), Duration: 25 * time.Minute, Completed: true},
+		{ID: "2", Mode: ShortBreakMode, Start: at(25), End: at(30), Duration: 5 * time.Minute, Completed: true},
+		{ID: "3", Mode: FocusMode, TaskID: docs.ID, Start: at(30), End: at(55), Duration: 25 * time.Minute, Completed: true, Segments: []TaskSegment{
+			{TaskID: docs.ID, Project: "acme", Start: at(30), End: at(35), Duration: 5 * time.Minute, Pomodoros: 0.2},
+			{TaskID: review.ID, Start: at(35), End: at(55), Duration: 20 * time.Minute, Pomodoros: 0.8},
+		}},
+		{ID: "4", Mode: FocusMode, TaskID: review.ID, Start: at(60), End: at(70), Duration: 10 * time.Minute},
+	})
+
+	tests := []struct {
+		groupBy       TimesheetGroupBy
+		wantKeys      []string
+		wantPomodoros []float64
+		wantSessions  []int
+		wantFocus     []time.Duration
+	}{
+		{GroupByTask, []string{review.ID, docs.ID}, []float64{0.8, 1.2}, []int{2, 2}, []time.Duration{30 * time.Minute, 30 * time.Minute}},
+		{GroupByProject, []string{NoProjectName, "acme"}, []float64{0.8, 1.2}, []int{2, 2}, []time.Duration{30 * time.Minute, 30 * time.Minute}},
+		{GroupByDay, []string{"2026-10-18"}, []float64{2}, []int{3}, []time.Duration{60 * time.Minute}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.groupBy), func(t *testing.T) {
+			sheet := BuildTimesheet(history, tm, day, day, tt.groupBy)
+			if sheet.Total.Pomodoros != 2 || sheet.Total.Sessions != 3 || sheet.Total.FocusTime != 60*time.Minute {
+				t.Errorf("total = %+v", sheet.Total)
+			}
+			if len(sheet.Rows) != len(tt.wantKeys) {
+				t.Fatalf("got %d rows, want %d: %+v", len(sheet.Rows), len(tt.wantKeys), sheet.Rows)
+			}
+			for i, row := range sheet.Rows {
+				if row.Key != tt.wantKeys[i] || math.Abs(row.Pomodoros-tt.wantPomodoros[i]) > 1e-9 || row.Sessions != tt.wantSessions[i] || row.FocusTime != tt.wantFocus[i] {
+					t.Errorf("row %d = %s %v pomodoros, %d sessions, %s; want %s %v, %d, %s", i,
+						row.Key, row.Pomodoros, row.Sessions, row.FocusTime,
+						tt.wantKeys[i], tt.wantPomodoros[i], tt.wantSessions[i], tt.wantFocus[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package storage
+
+import (
+	"github.com/jackrudenko/pomodorocli/model"
+)
+
+// HistoryStorage defines the interface for session history persistence
+type HistoryStorage interface {
+	// SaveHistory persists all sessions and returns any error
+	SaveHistory(sessions []model.Session) error
+
+	// LoadHistory retrieves all sessions
+	LoadHistory() ([]model.Session, error)
+}
@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/jackrudenko/pomodorocli/model"
+)
+
+// HistoryData represents the data structure stored in the history JSON file
+type HistoryData struct {
+	Sessions []model.Session `json:"sessions"`
+}
+
+// JSONHistoryStorage implements HistoryStorage using a local JSON file
+type JSONHistoryStorage struct {
+	filePath string
+}
+
+// NewJSONHistoryStorage creates a new JSONHistoryStorage instance
+func NewJSONHistoryStorage(filePath string) (*JSONHistoryStorage, error) {
+	// Ensure the directory exists
+	dir := filepath.Dir(filePath)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &JSONHistoryStorage{
+		filePath: filePath,
+	}, nil
+}
+
+// SaveHistory persists sessions to the JSON file
+func (j *JSONHistoryStorage) SaveHistory(sessions []model.Session) error {
+	if sessions == nil {
+		return errors.New("sessions cannot be nil")
+	}
+
+	// Marshal to JSON
+	jsonData, err := json.MarshalIndent(HistoryData{Sessions: sessions}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write to file
+	return os.WriteFile(j.filePath, jsonData, 0o644)
+}
+
+// LoadHistory retrieves sessions from the JSON file
+func (j *JSONHistoryStorage) LoadHistory() ([]model.Session, error) {
+	// Return empty history if the file doesn't exist yet
+	if _, err := os.Stat(j.filePath); os.IsNotExist(err) {
+		return make([]model.Session, 0), nil
+	}
+
+	// Read file
+	fileData, err := os.ReadFile(j.filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal JSON
+	var data HistoryData
+	if err := json.Unmarshal(fileData, &data); err != nil {
+		return nil, err
+	}
+
+	if data.Sessions == nil {
+		data.Sessions = make([]model.Session, 0)
+	}
+	return data.Sessions, nil
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
 
 	"github.com/jackrudenko/pomodorocli/model"
 )
@@ -96,7 +97,7 @@ func (j *JSONTaskStorage) LoadSettings() (model.Settings, error) {
 	}
 
 	// If settings is empty (old file format), return default settings
-	if (data.Settings == model.Settings{}) {
+	if reflect.DeepEqual(data.Settings, model.Settings{}) {
 		return model.DefaultSettings(), nil
 	}
 
@@ -121,8 +122,8 @@ func (j *JSONTaskStorage) readData() (TaskData, error) {
 		return TaskData{}, err
 	}
 
-	// Unmarshal JSON
-	var data TaskData
+	// Unmarshal JSON over the defaults so settings added in newer versions get sensible values
+	data := TaskData{Settings: model.DefaultSettings()}
 	if err := json.Unmarshal(fileData, &data); err != nil {
 		return TaskData{}, err
 	}
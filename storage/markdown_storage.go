@@ -0,0 +1,292 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jackrudenko/pomodorocli/model"
+	"github.com/segmentio/ksuid"
+)
+
+// markdownChecklistItem matches a checklist item such as "  - [x] Write docs", capturing the
+// indentation, the list marker, the check mark and the text
+var markdownChecklistItem = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+\[([ xX])\]\s+(.*)$`)
+
+// markdownMetadata matches the HTML comment holding a task's pomodoro data
+var markdownMetadata = regexp.MustCompile(`\s*<!--\s*pomodoro:?\s*(.*?)\s*-->`)
+
+// MarkdownStorage implements TaskStorage over the checklist items ("- [ ]" and "- [x]") of
+// a Markdown file such as TODO.md. Pomodoro data is kept in a trailing HTML comment that
+// renderers hide, nested items are subtasks, and headings, prose and code blocks are written
+// back unchanged. Taskwarrior annotations are not stored.
+type MarkdownStorage struct {
+	filePath string
+	// Lines of the file as last loaded or saved, in order
+	lines []markdownLine
+}
+
+// markdownLine is a line of the file: either a checklist item, or text kept verbatim
+type markdownLine struct {
+	taskID string
+	raw    string
+	// Indentation and list marker of a checklist item, e.g. "  -"
+	indent string
+	marker string
+	// Metadata tokens of a checklist item that the app doesn't understand
+	extra []string
+}
+
+// NewMarkdownStorage creates a new MarkdownStorage instance
+func NewMarkdownStorage(filePath string) (*MarkdownStorage, error) {
+	// Ensure the directory exists
+	dir := filepath.Dir(filePath)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &MarkdownStorage{
+		filePath: filePath,
+	}, nil
+}
+
+// Load reads the checklist items of the Markdown file as tasks
+func (s *MarkdownStorage) Load() ([]model.Task, error) {
+	s.lines = nil
+	tasks := make([]model.Task, 0)
+
+	content, err := os.ReadFile(s.filePath)
+	if os.IsNotExist(err) {
+		// Return empty list if file doesn't exist yet
+		return tasks, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Items nested under another item are its subtasks
+	type openItem struct {
+		indent int
+		taskID string
+	}
+	var parents []openItem
+	inCodeBlock := false
+
+	for _, raw := range strings.Split(strings.TrimRight(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n"), "\n") {
+		trimmed := strings.TrimSpace(raw)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inCodeBlock = !inCodeBlock
+		}
+
+		match := markdownChecklistItem.FindStringSubmatch(raw)
+		if inCodeBlock || match == nil {
+			// Headings and unindented prose end the current list
+			if trimmed != "" && !strings.HasPrefix(raw, " ") && !strings.HasPrefix(raw, "\t") {
+				parents = nil
+			}
+			s.lines = append(s.lines, markdownLine{raw: raw})
+			continue
+		}
+
+		task, extra := parseMarkdownItem(match[3], match[4])
+
+		indent := len(strings.ReplaceAll(match[1], "\t", "    "))
+		for len(parents) > 0 && parents[len(parents)-1].indent >= indent {
+			parents = parents[:len(parents)-1]
+		}
+		if task.ParentID == "" && len(parents) > 0 {
+			task.ParentID = parents[len(parents)-1].taskID
+		}
+		parents = append(parents, openItem{indent: indent, taskID: task.ID})
+
+		tasks = append(tasks, task)
+		s.lines = append(s.lines, markdownLine{taskID: task.ID, raw: raw, indent: match[1], marker: match[2], extra: extra})
+	}
+
+	return tasks, nil
+}
+
+// Save writes tasks to the Markdown file, keeping the order of the items and everything
+// around them. New tasks are added after the last item, and new subtasks after their parent's items.
+func (s *MarkdownStorage) Save(tasks []model.Task) error {
+	if tasks == nil {
+		return errors.New("tasks cannot be nil")
+	}
+
+	byID := make(map[string]model.Task, len(tasks))
+	for _, task := range tasks {
+		byID[task.ID] = task
+	}
+
+	lines := make([]markdownLine, 0, len(s.lines))
+	written := make(map[string]bool, len(tasks))
+	for _, line := range s.lines {
+		if line.taskID == "" {
+			lines = append(lines, line)
+			continue
+		}
+		// Deleted tasks are dropped
+		if task, ok := byID[line.taskID]; ok {
+			line.raw = formatMarkdownItem(task, line.indent, line.marker, line.extra)
+			lines = append(lines, line)
+			written[task.ID] = true
+		}
+	}
+
+	for _, task := range tasks {
+		if written[task.ID] {
+			continue
+		}
+		line := markdownLine{taskID: task.ID, marker: "-"}
+		// Subtasks are nested one level below their parent
+		for _, existing := range lines {
+			if task.ParentID != "" && existing.taskID == task.ParentID {
+				line.indent = existing.indent + "  "
+			}
+		}
+		line.raw = formatMarkdownItem(task, line.indent, line.marker, nil)
+
+		at := markdownInsertIndex(lines, task.ParentID)
+		lines = append(lines, markdownLine{})
+		copy(lines[at+1:], lines[at:])
+		lines[at] = line
+		written[task.ID] = true
+	}
+
+	var builder strings.Builder
+	for _, line := range lines {
+		builder.WriteString(line.raw)
+		builder.WriteString("\n")
+	}
+	if err := os.WriteFile(s.filePath, []byte(builder.String()), 0o644); err != nil {
+		return err
+	}
+
+	s.lines = lines
+	return nil
+}
+
+// markdownInsertIndex returns where a new item goes: after the items nested under its parent,
+// or after the last item of the file for top-level tasks and tasks whose parent isn't in the file
+func markdownInsertIndex(lines []markdownLine, parentID string) int {
+	last := len(lines)
+	for i := len(lines) - 1; i >= 0; i-- {
+		if lines[i].taskID != "" {
+			last = i + 1
+			break
+		}
+	}
+	if parentID == "" {
+		return last
+	}
+
+	for i, line := range lines {
+		if line.taskID != parentID {
+			continue
+		}
+		// Skip the items indented deeper than the parent
+		at := i + 1
+		for at < len(lines) && lines[at].taskID != "" && len(lines[at].indent) > len(line.indent) {
+			at++
+		}
+		return at
+	}
+	return last
+}
+
+// parseMarkdownItem parses the check mark and text of a checklist item, returning the
+// metadata tokens it doesn't understand separately
+func parseMarkdownItem(mark, text string) (model.Task, []string) {
+	task := model.Task{PlannedPomodoros: todoTxtDefaultPomodoros}
+	extra := make([]string, 0)
+	completed := mark != " "
+	var completedAt time.Time
+
+	if match := markdownMetadata.FindStringSubmatchIndex(text); match != nil {
+		for _, token := range strings.Fields(text[match[2]:match[3]]) {
+			key, value, ok := strings.Cut(token, ":")
+			if !ok {
+				extra = append(extra, token)
+				continue
+			}
+
+			switch key {
+			case "project":
+				task.Project = value
+			case "tags":
+				task.Tags = strings.Split(value, ",")
+			case "priority":
+				task.Priority = value
+			case "created":
+				if date, err := time.ParseInLocation(todoTxtDateLayout, value, time.Local); err == nil {
+					task.CreatedAt = date
+				} else {
+					extra = append(extra, token)
+				}
+			case "done":
+				if date, err := time.ParseInLocation(todoTxtDateLayout, value, time.Local); err == nil {
+					completedAt = date
+				} else {
+					extra = append(extra, token)
+				}
+			default:
+				if !applyTodoTxtExtension(&task, key, value) {
+					extra = append(extra, token)
+				}
+			}
+		}
+		text = text[:match[0]] + text[match[1]:]
+	}
+	task.Description = strings.TrimSpace(text)
+
+	// Items ticked off by hand are completed when the file is read
+	if completed {
+		if completedAt.IsZero() {
+			completedAt = time.Now()
+		}
+		task.SetCompleted(true, completedAt)
+	}
+
+	// Items written by hand get an ID on the next save
+	if task.ID == "" {
+		task.ID = ksuid.New().String()
+	}
+	if task.OriginalEstimate == 0 {
+		task.OriginalEstimate = task.PlannedPomodoros
+	}
+	if task.CreatedAt.IsZero() {
+		task.CreatedAt = time.Now()
+	}
+
+	return task, extra
+}
+
+// formatMarkdownItem formats a task as a checklist item followed by its metadata comment
+func formatMarkdownItem(task model.Task, indent, marker string, extra []string) string {
+	mark := " "
+	if task.Completed {
+		mark = "x"
+	}
+
+	tokens := make([]string, 0)
+	if task.Project != "" {
+		tokens = append(tokens, "project:"+task.Project)
+	}
+	if len(task.Tags) > 0 {
+		tokens = append(tokens, "tags:"+strings.Join(task.Tags, ","))
+	}
+	if task.Priority != "" {
+		tokens = append(tokens, "priority:"+task.Priority)
+	}
+	tokens = append(tokens, "created:"+task.CreatedAt.Format(todoTxtDateLayout))
+	if task.Completed && task.CompletedAt != nil {
+		tokens = append(tokens, "done:"+task.CompletedAt.Format(todoTxtDateLayout))
+	}
+	tokens = append(tokens, taskExtensions(task)...)
+	tokens = append(tokens, extra...)
+
+	return indent + marker + " [" + mark + "] " + task.Description + " <!-- pomodoro " + strings.Join(tokens, " ") + " -->"
+}
@@ -0,0 +1,152 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackrudenko/pomodorocli/model"
+)
+
+func TestMarkdownRoundTripKeepsFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{
+			name:    "items and prose",
+			content: "# TODO\n\nSome notes.\n\n- [ ] Fix login bug <!-- pomodoro created:2026-10-01 pom:1/4 spent:25m id:a -->\n",
+		},
+		{
+			name:    "subtasks",
+			content: "- [ ] Fix login bug <!-- pomodoro created:2026-10-01 pom:0/4 id:a -->\n  - [ ] Write regression test <!-- pomodoro created:2026-10-01 pom:0/2 parent:a id:b -->\n",
+		},
+		{
+			name:    "completed item with metadata",
+			content: "* [x] Ship it <!-- pomodoro project:acme tags:api,docs priority:H created:2026-10-01 done:2026-10-18 every:weekdays series:s1 next:b pom:4/4 id:a -->\n",
+		},
+		{
+			name:    "notes and re-estimates",
+			content: "- [ ] Fix login bug <!-- pomodoro created:2026-10-01 pom:0/3 est:2 reest:20261018T090000Z,2,3 note:See+%3C%21--+the+logs+--%3E id:a -->\n",
+		},
+		{
+			name:    "unknown tokens",
+			content: "- [ ] Fix login bug <!-- pomodoro created:2026-10-01 pom:0/4 id:a owner:me -->\n",
+		},
+		{
+			name:    "code block",
+			content: "```\n- [ ] not a task\n```\n- [ ] Fix login bug <!-- pomodoro created:2026-10-01 pom:0/4 id:a -->\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeTestFile(t, "TODO.md", tt.content)
+			storage, err := NewMarkdownStorage(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tasks, err := storage.Load()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := storage.Save(tasks); err != nil {
+				t.Fatal(err)
+			}
+			if got := readTestFile(t, path); got != tt.content {
+				t.Errorf("saved\n%q\nwant\n%q", got, tt.content)
+			}
+		})
+	}
+}
+
+func TestMarkdownLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		check   func(t *testing.T, tasks []model.Task)
+	}{
+		{
+			name:    "nested items are subtasks",
+			content: "- [ ] Parent <!-- pomodoro id:a -->\n  - [ ] Child <!-- pomodoro id:b -->\n- [ ] Sibling <!-- pomodoro id:c -->\n",
+			check: func(t *testing.T, tasks []model.Task) {
+				if len(tasks) != 3 || tasks[1].ParentID != "a" || tasks[2].ParentID != "" {
+					t.Errorf("tasks %+v", tasks)
+				}
+			},
+		},
+		{
+			name:    "ticked items are completed",
+			content: "- [x] Done <!-- pomodoro done:2026-10-18 id:a -->\n",
+			check: func(t *testing.T, tasks []model.Task) {
+				if !tasks[0].Completed || todoTxtDate(tasks[0].CompletedAt) != "2026-10-18" {
+					t.Errorf("task %+v", tasks[0])
+				}
+			},
+		},
+		{
+			name:    "items without metadata",
+			content: "- [ ] Write docs\n",
+			check: func(t *testing.T, tasks []model.Task) {
+				if tasks[0].Description != "Write docs" || tasks[0].ID == "" || tasks[0].PlannedPomodoros != todoTxtDefaultPomodoros {
+					t.Errorf("task %+v", tasks[0])
+				}
+			},
+		},
+		{
+			name:    "notes",
+			content: "- [ ] Fix login bug <!-- pomodoro note:See+%3C%21--+the+logs+--%3E%0Afirst id:a -->\n",
+			check: func(t *testing.T, tasks []model.Task) {
+				if tasks[0].Notes != "See <!-- the logs -->\nfirst" || tasks[0].Description != "Fix login bug" {
+					t.Errorf("task %+v", tasks[0])
+				}
+			},
+		},
+		{
+			name:    "series links",
+			content: "- [ ] Water plants <!-- pomodoro every:3d series:s1 next:b id:a -->\n",
+			check: func(t *testing.T, tasks []model.Task) {
+				if tasks[0].SeriesID != "s1" || tasks[0].NextOccurrenceID != "b" || tasks[0].Recurrence == nil {
+					t.Errorf("task %+v", tasks[0])
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage, err := NewMarkdownStorage(writeTestFile(t, "TODO.md", tt.content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			tasks, err := storage.Load()
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, tasks)
+		})
+	}
+}
+
+func TestMarkdownSaveNestsNewSubtasks(t *testing.T) {
+	path := writeTestFile(t, "TODO.md", "## Backend\n- [ ] Fix login bug <!-- pomodoro created:2026-10-01 pom:0/4 id:a -->\n\n## Frontend\n")
+	storage, err := NewMarkdownStorage(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tasks, err := storage.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	created := time.Date(2026, 10, 18, 9, 0, 0, 0, time.Local)
+	subtask := model.Task{ID: "b", Description: "Write regression test", ParentID: "a", PlannedPomodoros: 2, OriginalEstimate: 2, CreatedAt: created}
+	if err := storage.Save(append(tasks, subtask)); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "## Backend\n- [ ] Fix login bug <!-- pomodoro created:2026-10-01 pom:0/4 id:a -->\n" +
+		"  - [ ] Write regression test <!-- pomodoro created:2026-10-18 pom:0/2 parent:a id:b -->\n\n## Frontend\n"
+	if got := readTestFile(t, path); got != want {
+		t.Errorf("saved\n%q\nwant\n%q", got, want)
+	}
+}
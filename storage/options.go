@@ -0,0 +1,164 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/jackrudenko/pomodorocli/model"
+)
+
+const (
+	// legacyDataDirName is the directory earlier versions kept their files in, relative to where they ran
+	legacyDataDirName = "data"
+	// Files in the data directory
+	tasksFileName      = "tasks.json"
+	historyFileName    = "history.json"
+	workspacesFileName = "workspaces.json"
+)
+
+// Options selects the files tasks, settings and the session history are stored in
+type Options struct {
+	// JSON file holding the settings, and the tasks unless another task file is set
+	TasksPath string
+	// JSON file holding the session history
+	HistoryPath string
+	// todo.txt file to store the tasks in instead of the JSON file (empty if unused)
+	TodoTxtPath string
+	// Markdown checklist to store the tasks in instead of the JSON file (empty if unused)
+	MarkdownPath string
+	// JSON file listing the workspaces used before (empty to not record them)
+	WorkspacesPath string
+	// Workspace whose tasks and setting overrides are used (nil for the global task list)
+	Workspace *Workspace
+}
+
+// DefaultDataDir returns the directory holding the global tasks, settings and history:
+// pomodorocli in the user's configuration directory, e.g. ~/.config/pomodorocli
+func DefaultDataDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return legacyDataDirName
+	}
+	return filepath.Join(dir, "pomodorocli")
+}
+
+// DataDirOptions returns the options for the files in a data directory
+func DataDirOptions(dir string) Options {
+	return Options{
+		TasksPath:      filepath.Join(dir, tasksFileName),
+		HistoryPath:    filepath.Join(dir, historyFileName),
+		WorkspacesPath: filepath.Join(dir, workspacesFileName),
+	}
+}
+
+// DefaultOptions returns the options for the default data directory
+func DefaultOptions() Options {
+	return DataDirOptions(DefaultDataDir())
+}
+
+// legacyDataDirs returns where earlier versions may have kept their files: the data directory
+// under the working directory they were run from, and the one next to the executable
+func legacyDataDirs() []string {
+	dirs := make([]string, 0, 2)
+	if dir, err := filepath.Abs(legacyDataDirName); err == nil {
+		dirs = append(dirs, dir)
+	}
+	if executable, err := os.Executable(); err == nil {
+		if resolved, err := filepath.EvalSymlinks(executable); err == nil {
+			executable = resolved
+		}
+		if dir := filepath.Join(filepath.Dir(executable), legacyDataDirName); len(dirs) == 0 || dirs[0] != dir {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// MigrateLegacyData copies the tasks and history that earlier versions kept in a data directory
+// under the working directory or next to the executable into the given data directory, if that
+// is missing or empty. It returns the directory copied from, or "" if nothing was copied.
+func MigrateLegacyData(dir string) (string, error) {
+	dataDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	// Never mix old files into a data directory already in use
+	entries, err := os.ReadDir(dataDir)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	if len(entries) > 0 {
+		return "", nil
+	}
+
+	legacyDir := ""
+	for _, candidate := range legacyDataDirs() {
+		if candidate == dataDir {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(candidate, tasksFileName)); err == nil {
+			legacyDir = candidate
+			break
+		}
+	}
+	if legacyDir == "" {
+		return "", nil
+	}
+
+	if err := os.MkdirAll(dataDir, 0o755); err != nil {
+		return "", err
+	}
+	for _, name := range []string{tasksFileName, historyFileName} {
+		content, err := os.ReadFile(filepath.Join(legacyDir, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if err := os.WriteFile(filepath.Join(dataDir, name), content, 0o644); err != nil {
+			return "", err
+		}
+	}
+	return legacyDir, nil
+}
+
+// OpenStorageManager creates a StorageManager for the files selected by the options
+func OpenStorageManager(options Options, taskManager *model.TaskManager, settings *model.Settings, history *model.History) (*StorageManager, error) {
+	jsonStorage, err := NewJSONTaskStorage(options.TasksPath)
+	if err != nil {
+		return nil, err
+	}
+
+	historyStorage, err := NewJSONHistoryStorage(options.HistoryPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// The global JSON storage always holds the settings, and holds the tasks unless another file is set
+	var taskStorage TaskStorage = jsonStorage
+	var settingsStorage SettingsStorage = jsonStorage
+	if options.Workspace != nil {
+		if taskStorage, err = options.Workspace.openTaskStorage(); err != nil {
+			return nil, err
+		}
+		settingsStorage = &workspaceSettingsStorage{global: jsonStorage, workspace: options.Workspace}
+	}
+
+	switch {
+	case options.TodoTxtPath != "" && options.MarkdownPath != "":
+		return nil, errors.New("tasks can be stored in a todo.txt file or a Markdown file, not both")
+	case options.TodoTxtPath != "":
+		if taskStorage, err = NewTodoTxtStorage(options.TodoTxtPath); err != nil {
+			return nil, err
+		}
+	case options.MarkdownPath != "":
+		if taskStorage, err = NewMarkdownStorage(options.MarkdownPath); err != nil {
+			return nil, err
+		}
+	}
+
+	return NewStorageManager(taskStorage, settingsStorage, historyStorage, taskManager, settings, history), nil
+}
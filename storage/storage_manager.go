@@ -1,25 +1,45 @@
 package storage
 
 import (
+	"fmt"
+
 	"github.com/jackrudenko/pomodorocli/model"
 )
 
-// StorageManager handles loading and saving tasks and settings using the provided storage
+// StorageManager handles loading and saving tasks, settings and session history using the provided storage
 type StorageManager struct {
 	storage         TaskStorage
 	settingsStorage SettingsStorage
+	historyStorage  HistoryStorage
 	taskManager     *model.TaskManager
 	settings        *model.Settings
+	history         *model.History
 }
 
 // NewStorageManager creates a new StorageManager
-func NewStorageManager(storage TaskStorage, settingsStorage SettingsStorage, taskManager *model.TaskManager, settings *model.Settings) *StorageManager {
+func NewStorageManager(storage TaskStorage, settingsStorage SettingsStorage, historyStorage HistoryStorage, taskManager *model.TaskManager, settings *model.Settings, history *model.History) *StorageManager {
 	return &StorageManager{
 		storage:         storage,
 		settingsStorage: settingsStorage,
+		historyStorage:  historyStorage,
 		taskManager:     taskManager,
 		settings:        settings,
+		history:         history,
+	}
+}
+
+// LoadAll loads tasks, settings and the session history, stopping at the first error
+func (sm *StorageManager) LoadAll() error {
+	if err := sm.LoadTasks(); err != nil {
+		return fmt.Errorf("loading tasks: %w", err)
+	}
+	if err := sm.LoadSettings(); err != nil {
+		return fmt.Errorf("loading settings: %w", err)
 	}
+	if err := sm.LoadHistory(); err != nil {
+		return fmt.Errorf("loading history: %w", err)
+	}
+	return nil
 }
 
 // LoadTasks loads tasks from storage into the task manager
@@ -55,6 +75,22 @@ func (sm *StorageManager) SaveSettings() error {
 	return sm.settingsStorage.SaveSettings(*sm.settings)
 }
 
+// LoadHistory loads the session history from storage
+func (sm *StorageManager) LoadHistory() error {
+	sessions, err := sm.historyStorage.LoadHistory()
+	if err != nil {
+		return err
+	}
+
+	sm.history.LoadSessions(sessions)
+	return nil
+}
+
+// SaveHistory saves the session history to storage
+func (sm *StorageManager) SaveHistory() error {
+	return sm.historyStorage.SaveHistory(sm.history.GetSessions())
+}
+
 // AutoSave returns a function that can be called after task operations to autosave
 func (sm *StorageManager) AutoSave() func() {
 	return func() {
@@ -70,3 +106,11 @@ func (sm *StorageManager) AutoSaveSettings() func() {
 		_ = sm.SaveSettings()
 	}
 }
+
+// AutoSaveHistory returns a function that can be called after a session is recorded to autosave
+func (sm *StorageManager) AutoSaveHistory() func(session model.Session) {
+	return func(session model.Session) {
+		// Ignore errors during autosave
+		_ = sm.SaveHistory()
+	}
+}
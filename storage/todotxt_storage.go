@@ -0,0 +1,635 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackrudenko/pomodorocli/model"
+	"github.com/segmentio/ksuid"
+)
+
+// Layout of the dates in todo.txt lines
+const todoTxtDateLayout = "2006-01-02"
+
+// todoTxtExtension matches key:value extensions such as "due:2026-10-20" (but not URLs)
+var todoTxtExtension = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9_-]*):([^\s:/][^\s:]*)$`)
+
+// Planned pomodoros for lines without a pom:done/planned extension, as in the add task view
+const todoTxtDefaultPomodoros = 4
+
+// todoTxtPriorities maps todo.txt priorities to task priorities; other letters are kept as they are
+var todoTxtPriorities = map[string]string{"A": "H", "B": "M", "C": "L"}
+
+// TodoTxtStorage implements TaskStorage over a todo.txt file. Pomodoro data is kept in
+// key:value extensions (pom:2/4 spent:50m id:...), the first +project is the task's project
+// and @contexts are its tags. Lines and tokens the app doesn't understand are written back
+// unchanged, and tasks keep the order of their tokens. Taskwarrior annotations are not stored.
+type TodoTxtStorage struct {
+	filePath string
+	// Lines of the file as last loaded or saved, in order
+	lines []todoTxtLine
+}
+
+// todoTxtLine is a line of the file: either a task, or text kept verbatim
+type todoTxtLine struct {
+	taskID string
+	raw    string
+	// Task as last read from or written to raw, to tell which of its fields changed since
+	task model.Task
+}
+
+// todoTxtHeader is the completion mark, completion date, priority and creation date that
+// may start a task line, in that order
+type todoTxtHeader struct {
+	completed     bool
+	completedDate string
+	priority      string
+	createdDate   string
+}
+
+// NewTodoTxtStorage creates a new TodoTxtStorage instance
+func NewTodoTxtStorage(filePath string) (*TodoTxtStorage, error) {
+	// Ensure the directory exists
+	dir := filepath.Dir(filePath)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &TodoTxtStorage{
+		filePath: filePath,
+	}, nil
+}
+
+// Load reads tasks from the todo.txt file
+func (s *TodoTxtStorage) Load() ([]model.Task, error) {
+	s.lines = nil
+	tasks := make([]model.Task, 0)
+
+	content, err := os.ReadFile(s.filePath)
+	if os.IsNotExist(err) {
+		// Return empty list if file doesn't exist yet
+		return tasks, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, raw := range strings.Split(strings.TrimRight(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n"), "\n") {
+		// Blank lines and comments are kept as they are
+		if strings.TrimSpace(raw) == "" || strings.HasPrefix(strings.TrimSpace(raw), "#") {
+			s.lines = append(s.lines, todoTxtLine{raw: raw})
+			continue
+		}
+
+		task := parseTodoTxtLine(raw)
+		tasks = append(tasks, task)
+		s.lines = append(s.lines, todoTxtLine{taskID: task.ID, raw: raw, task: task})
+	}
+
+	return tasks, nil
+}
+
+// Save writes tasks to the todo.txt file, keeping the order and the unknown lines and tokens
+// of the file as last loaded. Only the tokens of changed fields are rewritten, and new tasks
+// are appended at the end.
+func (s *TodoTxtStorage) Save(tasks []model.Task) error {
+	if tasks == nil {
+		return errors.New("tasks cannot be nil")
+	}
+
+	byID := make(map[string]model.Task, len(tasks))
+	for _, task := range tasks {
+		byID[task.ID] = task
+	}
+
+	lines := make([]todoTxtLine, 0, len(tasks))
+	written := make(map[string]bool, len(tasks))
+	for _, line := range s.lines {
+		if line.taskID == "" {
+			lines = append(lines, line)
+			continue
+		}
+		// Deleted tasks are dropped
+		if task, ok := byID[line.taskID]; ok {
+			lines = append(lines, todoTxtLine{taskID: task.ID, raw: updateTodoTxtLine(line.raw, line.task, task), task: task})
+			written[task.ID] = true
+		}
+	}
+	for _, task := range tasks {
+		if !written[task.ID] {
+			lines = append(lines, todoTxtLine{taskID: task.ID, raw: formatTodoTxtLine(task), task: task})
+		}
+	}
+
+	var builder strings.Builder
+	for _, line := range lines {
+		builder.WriteString(line.raw)
+		builder.WriteString("\n")
+	}
+	if err := os.WriteFile(s.filePath, []byte(builder.String()), 0o644); err != nil {
+		return err
+	}
+
+	s.lines = lines
+	return nil
+}
+
+// parseTodoTxtHeader splits the header off the fields of a task line
+func parseTodoTxtHeader(fields []string) (todoTxtHeader, []string) {
+	var header todoTxtHeader
+	isDate := func(field string) bool {
+		_, err := time.ParseInLocation(todoTxtDateLayout, field, time.Local)
+		return err == nil
+	}
+
+	if len(fields) > 0 && fields[0] == "x" {
+		header.completed = true
+		fields = fields[1:]
+		if len(fields) > 0 && isDate(fields[0]) {
+			header.completedDate = fields[0]
+			fields = fields[1:]
+		}
+	}
+	if len(fields) > 0 && len(fields[0]) == 3 && fields[0][0] == '(' && fields[0][2] == ')' && fields[0][1] >= 'A' && fields[0][1] <= 'Z' {
+		header.priority = string(fields[0][1])
+		fields = fields[1:]
+	}
+	if len(fields) > 0 && isDate(fields[0]) {
+		header.createdDate = fields[0]
+		fields = fields[1:]
+	}
+	return header, fields
+}
+
+// parseTodoTxtLine parses a task line
+func parseTodoTxtLine(raw string) model.Task {
+	task := model.Task{PlannedPomodoros: todoTxtDefaultPomodoros}
+	header, fields := parseTodoTxtHeader(strings.Fields(raw))
+
+	if header.completed {
+		completedAt := time.Now()
+		if header.completedDate != "" {
+			completedAt, _ = time.ParseInLocation(todoTxtDateLayout, header.completedDate, time.Local)
+		}
+		task.SetCompleted(true, completedAt)
+	}
+	if header.priority != "" {
+		task.Priority = header.priority
+		if priority, ok := todoTxtPriorities[task.Priority]; ok {
+			task.Priority = priority
+		}
+	}
+	if header.createdDate != "" {
+		task.CreatedAt, _ = time.ParseInLocation(todoTxtDateLayout, header.createdDate, time.Local)
+	}
+
+	words := make([]string, 0, len(fields))
+	for _, field := range fields {
+		switch {
+		case strings.HasPrefix(field, "+") && len(field) > 1:
+			// Further projects are kept in the line only
+			if task.Project == "" {
+				task.Project = field[1:]
+			}
+
+		case strings.HasPrefix(field, "@") && len(field) > 1:
+			task.Tags = append(task.Tags, field[1:])
+
+		case todoTxtExtension.MatchString(field):
+			match := todoTxtExtension.FindStringSubmatch(field)
+			applyTodoTxtExtension(&task, match[1], match[2])
+
+		default:
+			words = append(words, field)
+		}
+	}
+	task.Description = strings.Join(words, " ")
+
+	// Lines written by hand get an ID on the next save
+	if task.ID == "" {
+		task.ID = ksuid.New().String()
+	}
+	if task.OriginalEstimate == 0 {
+		task.OriginalEstimate = task.PlannedPomodoros
+	}
+	if task.CreatedAt.IsZero() {
+		task.CreatedAt = time.Now()
+	}
+
+	return task
+}
+
+// applyTodoTxtExtension applies a key:value extension, reporting false if the app doesn't use it
+func applyTodoTxtExtension(task *model.Task, key, value string) bool {
+	switch key {
+	case "id":
+		task.ID = value
+
+	case "parent":
+		task.ParentID = value
+
+	case "uuid":
+		task.TaskwarriorUUID = value
+
+	case "series":
+		task.SeriesID = value
+
+	case "next":
+		task.NextOccurrenceID = value
+
+	case "pom":
+		completed, planned, ok := strings.Cut(value, "/")
+		completedCount, errCompleted := strconv.Atoi(completed)
+		plannedCount, errPlanned := strconv.Atoi(planned)
+		if !ok || errCompleted != nil || errPlanned != nil {
+			return false
+		}
+		task.CompletedPomodoros = completedCount
+		task.PlannedPomodoros = plannedCount
+
+	case "frac":
+		fraction, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return false
+		}
+		task.PomodoroFraction = fraction
+
+	case "est":
+		estimate, err := strconv.Atoi(value)
+		if err != nil {
+			return false
+		}
+		task.OriginalEstimate = estimate
+
+	case "spent":
+		spent, err := time.ParseDuration(value)
+		if err != nil {
+			return false
+		}
+		task.TimeSpent = spent
+
+	case "due":
+		due, err := time.ParseInLocation(todoTxtDateLayout, value, time.Local)
+		if err != nil {
+			return false
+		}
+		task.Due = &due
+
+	case "every":
+		recurrence, err := model.ParseRecurrence(value)
+		if err != nil {
+			return false
+		}
+		task.Recurrence = recurrence
+
+	case "timer":
+		durations, err := model.ParseTaskDurations(value)
+		if err != nil {
+			return false
+		}
+		task.Durations = durations
+
+	case "archived":
+		task.Archived = value == "1"
+
+	case "git":
+		repo, branch, ok := strings.Cut(value, ",")
+		repo, errRepo := url.QueryUnescape(repo)
+		branch, errBranch := url.QueryUnescape(branch)
+		if !ok || errRepo != nil || errBranch != nil {
+			return false
+		}
+		task.GitRepo = repo
+		task.GitBranch = branch
+
+	case "code":
+		comment, ok := parseTodoTxtCodeComment(value)
+		if !ok {
+			return false
+		}
+		task.CodeComment = &comment
+
+	case "note":
+		notes, err := url.QueryUnescape(value)
+		if err != nil {
+			return false
+		}
+		task.Notes = notes
+
+	case "reest":
+		reestimates, ok := parseTodoTxtReestimates(value)
+		if !ok {
+			return false
+		}
+		task.Reestimates = reestimates
+
+	case "trashed":
+		trashedAt, err := time.ParseInLocation(todoTxtDateLayout, value, time.Local)
+		if err != nil {
+			return false
+		}
+		task.TrashedAt = &trashedAt
+
+	default:
+		return false
+	}
+	return true
+}
+
+// formatTodoTxtLine formats a new task as a todo.txt line
+func formatTodoTxtLine(task model.Task) string {
+	parts := formatTodoTxtHeader(task, true, true)
+	parts = append(parts, task.Description)
+	if task.Project != "" {
+		parts = append(parts, "+"+task.Project)
+	}
+	for _, tag := range task.Tags {
+		parts = append(parts, "@"+tag)
+	}
+	parts = append(parts, taskExtensions(task)...)
+	return strings.Join(parts, " ")
+}
+
+// todoTxtDate formats an optional date, or returns "" if it is unset
+func todoTxtDate(date *time.Time) string {
+	if date == nil {
+		return ""
+	}
+	return date.Format(todoTxtDateLayout)
+}
+
+// formatTodoTxtHeader formats the completion mark and priority of a task, with its completion
+// and creation dates if they are wanted
+func formatTodoTxtHeader(task model.Task, completedDate, createdDate bool) []string {
+	parts := make([]string, 0, 4)
+	if task.Completed {
+		parts = append(parts, "x")
+		if completedDate && task.CompletedAt != nil {
+			parts = append(parts, task.CompletedAt.Format(todoTxtDateLayout))
+		}
+	}
+	if task.Priority != "" {
+		priority := task.Priority
+		for letter, mapped := range todoTxtPriorities {
+			if mapped == task.Priority {
+				priority = letter
+			}
+		}
+		parts = append(parts, "("+priority+")")
+	}
+	if createdDate {
+		parts = append(parts, task.CreatedAt.Format(todoTxtDateLayout))
+	}
+	return parts
+}
+
+// updateTodoTxtLine rewrites the line of a task that was read as before so that it holds the
+// task as it is now. Tokens stay where they are and keep their spelling unless their field
+// changed, tokens the app doesn't understand are kept, and new tokens are added at the end.
+// Dates the line didn't have are only added when the task is completed.
+func updateTodoTxtLine(raw string, before, after model.Task) string {
+	fields := strings.Fields(raw)
+	header, body := parseTodoTxtHeader(fields)
+
+	// The header is kept as written unless the completion or priority changed
+	parts := append([]string(nil), fields[:len(fields)-len(body)]...)
+	if before.Completed != after.Completed || todoTxtDate(before.CompletedAt) != todoTxtDate(after.CompletedAt) || before.Priority != after.Priority {
+		completedDate := header.completedDate != "" || (after.Completed && !before.Completed)
+		parts = formatTodoTxtHeader(after, completedDate, header.createdDate != "")
+	}
+	headerLength := len(parts)
+
+	// Tokens of the known extensions, by key
+	extensionKeys := func(tokens []string) map[string]string {
+		byKey := make(map[string]string, len(tokens))
+		for _, token := range tokens {
+			key, _, _ := strings.Cut(token, ":")
+			byKey[key] = token
+		}
+		return byKey
+	}
+	previous := extensionKeys(taskExtensions(before))
+	current := extensionKeys(taskExtensions(after))
+
+	tags := make(map[string]bool, len(after.Tags))
+	for _, tag := range after.Tags {
+		tags[tag] = true
+	}
+
+	descriptionChanged := before.Description != after.Description
+	descriptionDone, projectDone := false, false
+	emitted := make(map[string]bool)
+	for _, field := range body {
+		switch {
+		case strings.HasPrefix(field, "+") && len(field) > 1:
+			// Only the first project is the task's
+			if projectDone {
+				parts = append(parts, field)
+				continue
+			}
+			projectDone = true
+			if after.Project == before.Project {
+				parts = append(parts, field)
+			} else if after.Project != "" {
+				parts = append(parts, "+"+after.Project)
+			}
+
+		case strings.HasPrefix(field, "@") && len(field) > 1:
+			if tag := field[1:]; tags[tag] && !emitted["@"+tag] {
+				emitted["@"+tag] = true
+				parts = append(parts, field)
+			}
+
+		case todoTxtExtension.MatchString(field):
+			match := todoTxtExtension.FindStringSubmatch(field)
+			if !applyTodoTxtExtension(&model.Task{}, match[1], match[2]) {
+				parts = append(parts, field)
+				continue
+			}
+			key := match[1]
+			token, ok := current[key]
+			if !ok || emitted[key] {
+				continue
+			}
+			emitted[key] = true
+			if token == previous[key] {
+				token = field
+			}
+			parts = append(parts, token)
+
+		default:
+			if !descriptionChanged {
+				parts = append(parts, field)
+			} else if !descriptionDone {
+				descriptionDone = true
+				parts = append(parts, after.Description)
+			}
+		}
+	}
+
+	if descriptionChanged && !descriptionDone && after.Description != "" {
+		parts = append(parts[:headerLength], append([]string{after.Description}, parts[headerLength:]...)...)
+	}
+	if !projectDone && after.Project != "" {
+		parts = append(parts, "+"+after.Project)
+	}
+	for _, tag := range after.Tags {
+		if !emitted["@"+tag] {
+			emitted["@"+tag] = true
+			parts = append(parts, "@"+tag)
+		}
+	}
+	// New extensions are only added if their value changed, apart from the ID that lines
+	// written by hand still need
+	for _, token := range taskExtensions(after) {
+		key, _, _ := strings.Cut(token, ":")
+		if !emitted[key] && (token != previous[key] || key == "id") {
+			parts = append(parts, token)
+		}
+	}
+
+	if strings.Join(parts, " ") == strings.Join(fields, " ") {
+		return raw
+	}
+	return strings.Join(parts, " ")
+}
+
+// taskExtensions returns the key:value extensions holding a task's dates and pomodoro data,
+// in the order they are written
+func taskExtensions(task model.Task) []string {
+	parts := make([]string, 0)
+	if task.Due != nil {
+		parts = append(parts, "due:"+task.Due.Format(todoTxtDateLayout))
+	}
+	if task.Recurrence != nil {
+		parts = append(parts, "every:"+task.Recurrence.String())
+	}
+	if task.SeriesID != "" {
+		parts = append(parts, "series:"+task.SeriesID)
+	}
+	if task.NextOccurrenceID != "" {
+		parts = append(parts, "next:"+task.NextOccurrenceID)
+	}
+	if task.Durations != nil {
+		parts = append(parts, "timer:"+task.Durations.String())
+	}
+
+	parts = append(parts, fmt.Sprintf("pom:%d/%d", task.CompletedPomodoros, task.PlannedPomodoros))
+	if task.PomodoroFraction > 0 {
+		parts = append(parts, "frac:"+strconv.FormatFloat(task.PomodoroFraction, 'f', -1, 64))
+	}
+	if task.OriginalEstimate != 0 && task.OriginalEstimate != task.PlannedPomodoros {
+		parts = append(parts, fmt.Sprintf("est:%d", task.OriginalEstimate))
+	}
+	if len(task.Reestimates) > 0 {
+		parts = append(parts, "reest:"+formatTodoTxtReestimates(task.Reestimates))
+	}
+	if task.TimeSpent > 0 {
+		parts = append(parts, "spent:"+formatTodoTxtDuration(task.TimeSpent))
+	}
+	if task.ParentID != "" {
+		parts = append(parts, "parent:"+task.ParentID)
+	}
+	if task.Archived {
+		parts = append(parts, "archived:1")
+	}
+	if task.TrashedAt != nil {
+		parts = append(parts, "trashed:"+task.TrashedAt.Format(todoTxtDateLayout))
+	}
+	if task.TaskwarriorUUID != "" {
+		parts = append(parts, "uuid:"+task.TaskwarriorUUID)
+	}
+	if task.GitRepo != "" {
+		parts = append(parts, "git:"+url.QueryEscape(task.GitRepo)+","+url.QueryEscape(task.GitBranch))
+	}
+	if task.CodeComment != nil {
+		parts = append(parts, "code:"+formatTodoTxtCodeComment(*task.CodeComment))
+	}
+	if task.Notes != "" {
+		parts = append(parts, "note:"+url.QueryEscape(task.Notes))
+	}
+	return append(parts, "id:"+task.ID)
+}
+
+// todoTxtReestimateLayout is the layout of the time of a re-estimate, which can't contain colons
+const todoTxtReestimateLayout = "20060102T150405Z"
+
+// formatTodoTxtReestimates encodes re-estimates as a single token: the UTC time, old and new
+// planned pomodoros of each, separated by commas, with semicolons between re-estimates
+func formatTodoTxtReestimates(reestimates []model.Reestimate) string {
+	entries := make([]string, len(reestimates))
+	for i, reestimate := range reestimates {
+		entries[i] = fmt.Sprintf("%s,%d,%d", reestimate.At.UTC().Format(todoTxtReestimateLayout), reestimate.From, reestimate.To)
+	}
+	return strings.Join(entries, ";")
+}
+
+// parseTodoTxtReestimates decodes a token written by formatTodoTxtReestimates
+func parseTodoTxtReestimates(value string) ([]model.Reestimate, bool) {
+	reestimates := make([]model.Reestimate, 0)
+	for _, entry := range strings.Split(value, ";") {
+		fields := strings.Split(entry, ",")
+		if len(fields) != 3 {
+			return nil, false
+		}
+		at, errAt := time.Parse(todoTxtReestimateLayout, fields[0])
+		from, errFrom := strconv.Atoi(fields[1])
+		to, errTo := strconv.Atoi(fields[2])
+		if errAt != nil || errFrom != nil || errTo != nil {
+			return nil, false
+		}
+		reestimates = append(reestimates, model.Reestimate{At: at.Local(), From: from, To: to})
+	}
+	return reestimates, true
+}
+
+// formatTodoTxtCodeComment encodes the comment a task was imported from as a single token:
+// the escaped root, file, line and text separated by commas
+func formatTodoTxtCodeComment(comment model.CodeComment) string {
+	return strings.Join([]string{
+		url.QueryEscape(comment.Root),
+		url.QueryEscape(comment.File),
+		strconv.Itoa(comment.Line),
+		url.QueryEscape(comment.Text),
+	}, ",")
+}
+
+// parseTodoTxtCodeComment decodes a token written by formatTodoTxtCodeComment
+func parseTodoTxtCodeComment(value string) (model.CodeComment, bool) {
+	var comment model.CodeComment
+	fields := strings.Split(value, ",")
+	if len(fields) != 4 {
+		return comment, false
+	}
+
+	var errs [4]error
+	comment.Root, errs[0] = url.QueryUnescape(fields[0])
+	comment.File, errs[1] = url.QueryUnescape(fields[1])
+	comment.Line, errs[2] = strconv.Atoi(fields[2])
+	comment.Text, errs[3] = url.QueryUnescape(fields[3])
+	for _, err := range errs {
+		if err != nil {
+			return comment, false
+		}
+	}
+	return comment, true
+}
+
+// formatTodoTxtDuration formats a duration compactly, e.g. "1h25m" or "50m"
+func formatTodoTxtDuration(d time.Duration) string {
+	text := d.Round(time.Second).String()
+	if strings.HasSuffix(text, "m0s") {
+		text = strings.TrimSuffix(text, "0s")
+	}
+	if strings.HasSuffix(text, "h0m") {
+		text = strings.TrimSuffix(text, "0m")
+	}
+	return text
+}
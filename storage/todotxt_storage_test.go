@@ -0,0 +1,255 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackrudenko/pomodorocli/model"
+)
+
+// writeTestFile writes content to a file in a temporary directory and returns its path
+func writeTestFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// readTestFile returns the content of a file
+func readTestFile(t *testing.T, path string) string {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
+
+func TestTodoTxtRoundTripKeepsLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"comments and blank lines", "# Inbox\n\nCall mom id:a\n"},
+		{"header", "x 2026-10-18 (A) 2026-10-01 Call mom id:a\n"},
+		{"no creation date", "(B) Call mom pom:1/2 id:a\n"},
+		{"token order", "Call +family mom @phone rec:1w +extra t:2026-10-01 @home due:2026-10-20 id:a\n"},
+		{"extensions", "Ship it every:weekdays series:s1 next:b timer:50m/10m/20m pom:2/4 frac:0.5 est:3 spent:1h15m parent:p id:a\n"},
+		{"urls", "Read https://example.com/a:b id:a\n"},
+		{"notes and re-estimates", "Ship it reest:20261017T080000Z,2,4;20261018T090000Z,4,3 note:Ask+about%3A+the+trip%0Aand+bring+cake id:a\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeTestFile(t, "todo.txt", tt.content)
+			storage, err := NewTodoTxtStorage(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tasks, err := storage.Load()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := storage.Save(tasks); err != nil {
+				t.Fatal(err)
+			}
+			if got := readTestFile(t, path); got != tt.content {
+				t.Errorf("saved\n%q\nwant\n%q", got, tt.content)
+			}
+		})
+	}
+}
+
+func TestTodoTxtLoad(t *testing.T) {
+	path := writeTestFile(t, "todo.txt", "x 2026-10-18 (A) 2026-10-01 Call mom +family +extra @phone due:2026-10-20 every:mon,fri series:s1 next:b pom:2/4 frac:0.5 spent:50m id:a\n")
+	storage, err := NewTodoTxtStorage(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tasks, err := storage.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 1 {
+		t.Fatalf("got %d tasks, want 1", len(tasks))
+	}
+
+	task := tasks[0]
+	checks := []struct {
+		field string
+		got   interface{}
+		want  interface{}
+	}{
+		{"ID", task.ID, "a"},
+		{"Description", task.Description, "Call mom"},
+		{"Project", task.Project, "family"},
+		{"Tags", strings.Join(task.Tags, ","), "phone"},
+		{"Priority", task.Priority, "H"},
+		{"Completed", task.Completed, true},
+		{"CompletedAt", todoTxtDate(task.CompletedAt), "2026-10-18"},
+		{"CreatedAt", task.CreatedAt.Format(todoTxtDateLayout), "2026-10-01"},
+		{"Due", todoTxtDate(task.Due), "2026-10-20"},
+		{"Recurrence", task.Recurrence.String(), "mon,fri"},
+		{"SeriesID", task.SeriesID, "s1"},
+		{"NextOccurrenceID", task.NextOccurrenceID, "b"},
+		{"CompletedPomodoros", task.CompletedPomodoros, 2},
+		{"PlannedPomodoros", task.PlannedPomodoros, 4},
+		{"PomodoroFraction", task.PomodoroFraction, 0.5},
+		{"TimeSpent", task.TimeSpent, 50 * time.Minute},
+	}
+	for _, check := range checks {
+		if check.got != check.want {
+			t.Errorf("%s = %v, want %v", check.field, check.got, check.want)
+		}
+	}
+}
+
+func TestTodoTxtSaveChanges(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		change  func(task *model.Task)
+		want    string
+	}{
+		{
+			name:    "pomodoro count is replaced in place",
+			content: "Call mom pom:1/4 t:2026-10-01 id:a\n",
+			change:  func(task *model.Task) { task.CompletedPomodoros = 2 },
+			want:    "Call mom pom:2/4 t:2026-10-01 id:a\n",
+		},
+		{
+			name:    "new extensions are appended",
+			content: "Call mom t:2026-10-01 id:a\n",
+			change:  func(task *model.Task) { task.CompletedPomodoros = 1 },
+			want:    "Call mom t:2026-10-01 id:a pom:1/4\n",
+		},
+		{
+			name:    "completing adds the completion date only",
+			content: "(A) Call mom id:a\n",
+			change: func(task *model.Task) {
+				task.SetCompleted(true, time.Date(2026, 10, 18, 12, 0, 0, 0, time.Local))
+			},
+			want: "x 2026-10-18 (A) Call mom id:a\n",
+		},
+		{
+			name:    "removed tag is dropped, others stay",
+			content: "Call @phone mom @home id:a\n",
+			change:  func(task *model.Task) { task.Tags = []string{"home"} },
+			want:    "Call mom @home id:a\n",
+		},
+		{
+			name:    "changed project keeps further projects",
+			content: "Call mom +family +extra id:a\n",
+			change:  func(task *model.Task) { task.Project = "home" },
+			want:    "Call mom +home +extra id:a\n",
+		},
+		{
+			name:    "new description replaces the words",
+			content: "2026-10-01 Call +family mom id:a\n",
+			change:  func(task *model.Task) { task.Description = "Visit mom" },
+			want:    "2026-10-01 Visit mom +family id:a\n",
+		},
+		{
+			name:    "series links are stored",
+			content: "Water plants every:3d id:a\n",
+			change: func(task *model.Task) {
+				task.SeriesID = "a"
+				task.NextOccurrenceID = "b"
+			},
+			want: "Water plants every:3d id:a series:a next:b\n",
+		},
+		{
+			name:    "notes are stored",
+			content: "Call mom id:a\n",
+			change:  func(task *model.Task) { task.Notes = "Ask about: the trip\nand bring cake" },
+			want:    "Call mom id:a note:Ask+about%3A+the+trip%0Aand+bring+cake\n",
+		},
+		{
+			name:    "re-estimates are stored",
+			content: "Call mom pom:0/2 id:a\n",
+			change: func(task *model.Task) {
+				task.Reestimates = []model.Reestimate{{At: time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC), From: 2, To: 3}}
+				task.PlannedPomodoros = 3
+			},
+			want: "Call mom pom:0/3 id:a est:2 reest:20261018T090000Z,2,3\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeTestFile(t, "todo.txt", tt.content)
+			storage, err := NewTodoTxtStorage(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tasks, err := storage.Load()
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.change(&tasks[0])
+			if err := storage.Save(tasks); err != nil {
+				t.Fatal(err)
+			}
+			if got := readTestFile(t, path); got != tt.want {
+				t.Errorf("saved %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTodoTxtSaveAddsIDToHandWrittenLines(t *testing.T) {
+	path := writeTestFile(t, "todo.txt", "Call mom @phone\n")
+	storage, err := NewTodoTxtStorage(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tasks, err := storage.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.Save(tasks); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "Call mom @phone id:" + tasks[0].ID + "\n"
+	if got := readTestFile(t, path); got != want {
+		t.Errorf("saved %q, want %q", got, want)
+	}
+}
+
+func TestTodoTxtSaveAddsAndDeletesTasks(t *testing.T) {
+	path := writeTestFile(t, "todo.txt", "# Inbox\nCall mom id:a\nBuy milk id:b\n")
+	storage, err := NewTodoTxtStorage(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tasks, err := storage.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	created := time.Date(2026, 10, 18, 9, 0, 0, 0, time.Local)
+	added := model.Task{ID: "c", Description: "Write docs", Project: "acme", PlannedPomodoros: 2, OriginalEstimate: 2, CreatedAt: created}
+	if err := storage.Save([]model.Task{tasks[1], added}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "# Inbox\nBuy milk id:b\n2026-10-18 Write docs +acme pom:0/2 id:c\n"
+	if got := readTestFile(t, path); got != want {
+		t.Errorf("saved %q, want %q", got, want)
+	}
+
+	// The saved file loads back as the same tasks
+	reloaded, err := storage.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reloaded) != 2 || reloaded[1].ID != "c" || reloaded[1].Project != "acme" || reloaded[1].PlannedPomodoros != 2 {
+		t.Errorf("reloaded %+v", reloaded)
+	}
+}
@@ -0,0 +1,311 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackrudenko/pomodorocli/model"
+)
+
+const (
+	// WorkspaceDirName marks a workspace whose files live in a .pomodoro directory
+	WorkspaceDirName = ".pomodoro"
+	// WorkspaceFileName marks a workspace configured by a single file
+	WorkspaceFileName = ".pomodoro.json"
+	// Configuration file inside a .pomodoro directory (optional)
+	workspaceConfigName = "workspace.json"
+	// Task files used when the configuration doesn't name one
+	workspaceDirTasksName  = "tasks.json"
+	workspaceFileTasksName = ".pomodoro-tasks.json"
+)
+
+// Workspace is a project directory with its own task list and, optionally, settings that
+// override the global ones. It is marked by a .pomodoro directory or a .pomodoro.json file.
+type Workspace struct {
+	Name string
+	// Directory containing the marker
+	Root string
+	// File holding the name, task file and setting overrides (it may not exist yet)
+	ConfigPath string
+	// File holding the tasks: JSON, or todo.txt (.txt) or a Markdown checklist (.md)
+	TasksPath string
+	// Settings overriding the global ones, by JSON field name
+	Settings map[string]json.RawMessage
+	// Task file named by the configuration, kept as written when the configuration is saved
+	tasksName string
+}
+
+// workspaceConfig is the JSON representation of a workspace's configuration
+type workspaceConfig struct {
+	Name     string                     `json:"name,omitempty"`
+	Tasks    string                     `json:"tasks,omitempty"`
+	Settings map[string]json.RawMessage `json:"settings,omitempty"`
+}
+
+// FindWorkspace looks for a workspace marker in dir and its parents, like git does for
+// repositories, and returns nil if there is none
+func FindWorkspace(dir string) (*Workspace, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if isWorkspaceRoot(dir) {
+			return OpenWorkspace(dir)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// isWorkspaceRoot reports whether a directory contains a workspace marker
+func isWorkspaceRoot(dir string) bool {
+	if info, err := os.Stat(filepath.Join(dir, WorkspaceDirName)); err == nil && info.IsDir() {
+		return true
+	}
+	info, err := os.Stat(filepath.Join(dir, WorkspaceFileName))
+	return err == nil && !info.IsDir()
+}
+
+// OpenWorkspace reads the workspace whose marker is in root
+func OpenWorkspace(root string) (*Workspace, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	if !isWorkspaceRoot(root) {
+		return nil, fmt.Errorf("%s has no %s directory or %s file", root, WorkspaceDirName, WorkspaceFileName)
+	}
+
+	workspace := &Workspace{
+		Name:       filepath.Base(root),
+		Root:       root,
+		ConfigPath: filepath.Join(root, WorkspaceDirName, workspaceConfigName),
+		TasksPath:  filepath.Join(root, WorkspaceDirName, workspaceDirTasksName),
+	}
+	// The single-file form wins if both markers exist, as it is the more explicit one
+	if info, err := os.Stat(filepath.Join(root, WorkspaceFileName)); err == nil && !info.IsDir() {
+		workspace.ConfigPath = filepath.Join(root, WorkspaceFileName)
+		workspace.TasksPath = filepath.Join(root, workspaceFileTasksName)
+	}
+
+	content, err := os.ReadFile(workspace.ConfigPath)
+	if os.IsNotExist(err) {
+		// A bare .pomodoro directory uses the defaults
+		return workspace, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var config workspaceConfig
+	if err := json.Unmarshal(content, &config); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", workspace.ConfigPath, err)
+	}
+	if config.Name != "" {
+		workspace.Name = config.Name
+	}
+	if config.Tasks != "" {
+		workspace.tasksName = config.Tasks
+		workspace.TasksPath = config.Tasks
+		if !filepath.IsAbs(config.Tasks) {
+			workspace.TasksPath = filepath.Join(root, filepath.FromSlash(config.Tasks))
+		}
+	}
+	workspace.Settings = config.Settings
+
+	return workspace, nil
+}
+
+// ApplySettings returns the settings with the workspace's overrides applied
+func (w *Workspace) ApplySettings(settings model.Settings) (model.Settings, error) {
+	if len(w.Settings) == 0 {
+		return settings, nil
+	}
+
+	overrides, err := json.Marshal(w.Settings)
+	if err != nil {
+		return settings, err
+	}
+	if err := json.Unmarshal(overrides, &settings); err != nil {
+		return settings, fmt.Errorf("settings in %s: %w", w.ConfigPath, err)
+	}
+	return settings, nil
+}
+
+// saveConfig writes the workspace's configuration file
+func (w *Workspace) saveConfig() error {
+	config := workspaceConfig{Settings: w.Settings, Tasks: w.tasksName}
+	if w.Name != filepath.Base(w.Root) {
+		config.Name = w.Name
+	}
+
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(w.ConfigPath), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(w.ConfigPath, append(data, '\n'), 0o644)
+}
+
+// openTaskStorage opens the workspace's task file in the format given by its extension
+func (w *Workspace) openTaskStorage() (TaskStorage, error) {
+	switch strings.ToLower(filepath.Ext(w.TasksPath)) {
+	case ".txt":
+		return NewTodoTxtStorage(w.TasksPath)
+	case ".md", ".markdown":
+		return NewMarkdownStorage(w.TasksPath)
+	default:
+		return NewJSONTaskStorage(w.TasksPath)
+	}
+}
+
+// workspaceSettingsStorage layers a workspace's setting overrides over the global settings.
+// Changes to overridden settings are saved to the workspace, all others globally.
+type workspaceSettingsStorage struct {
+	global    SettingsStorage
+	workspace *Workspace
+}
+
+// LoadSettings loads the global settings and applies the workspace's overrides
+func (s *workspaceSettingsStorage) LoadSettings() (model.Settings, error) {
+	settings, err := s.global.LoadSettings()
+	if err != nil {
+		return settings, err
+	}
+	return s.workspace.ApplySettings(settings)
+}
+
+// SaveSettings saves overridden settings to the workspace and the others globally
+func (s *workspaceSettingsStorage) SaveSettings(settings model.Settings) error {
+	if len(s.workspace.Settings) == 0 {
+		return s.global.SaveSettings(settings)
+	}
+
+	fields, err := settingsFields(settings)
+	if err != nil {
+		return err
+	}
+	global, err := s.global.LoadSettings()
+	if err != nil {
+		return err
+	}
+	globalFields, err := settingsFields(global)
+	if err != nil {
+		return err
+	}
+
+	for key, value := range fields {
+		if _, overridden := s.workspace.Settings[key]; overridden {
+			s.workspace.Settings[key] = value
+		} else {
+			globalFields[key] = value
+		}
+	}
+	// Fields left out because they are empty were cleared
+	for key := range globalFields {
+		if _, ok := fields[key]; !ok {
+			delete(globalFields, key)
+		}
+	}
+
+	data, err := json.Marshal(globalFields)
+	if err != nil {
+		return err
+	}
+	var updated model.Settings
+	if err := json.Unmarshal(data, &updated); err != nil {
+		return err
+	}
+	if err := s.global.SaveSettings(updated); err != nil {
+		return err
+	}
+	return s.workspace.saveConfig()
+}
+
+// settingsFields returns the settings as JSON values by field name
+func settingsFields(settings model.Settings) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]json.RawMessage)
+	err = json.Unmarshal(data, &fields)
+	return fields, err
+}
+
+// KnownWorkspace is a workspace that has been used before, listed in the workspace switcher
+type KnownWorkspace struct {
+	Name     string    `json:"name"`
+	Root     string    `json:"root"`
+	LastUsed time.Time `json:"last_used"`
+}
+
+// LoadKnownWorkspaces reads the list of workspaces used before, most recently used first,
+// leaving out those whose marker has been removed
+func LoadKnownWorkspaces(path string) ([]KnownWorkspace, error) {
+	known := make([]KnownWorkspace, 0)
+
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return known, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(content, &known); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	existing := known[:0]
+	for _, workspace := range known {
+		if isWorkspaceRoot(workspace.Root) {
+			existing = append(existing, workspace)
+		}
+	}
+	sort.SliceStable(existing, func(i, j int) bool {
+		return existing[i].LastUsed.After(existing[j].LastUsed)
+	})
+	return existing, nil
+}
+
+// RememberWorkspace records that a workspace was used, so the switcher can offer it
+func RememberWorkspace(path string, workspace *Workspace, now time.Time) error {
+	if path == "" || workspace == nil {
+		return errors.New("no workspace list or workspace given")
+	}
+
+	known, err := LoadKnownWorkspaces(path)
+	if err != nil {
+		return err
+	}
+
+	entry := KnownWorkspace{Name: workspace.Name, Root: workspace.Root, LastUsed: now}
+	updated := []KnownWorkspace{entry}
+	for _, other := range known {
+		if other.Root != workspace.Root {
+			updated = append(updated, other)
+		}
+	}
+
+	data, err := json.MarshalIndent(updated, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
@@ -2,12 +2,16 @@ package ui
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/jackrudenko/pomodorocli/interop"
 	"github.com/jackrudenko/pomodorocli/model"
 	"github.com/jackrudenko/pomodorocli/storage"
 )
@@ -22,11 +26,38 @@ const (
 	AddTaskView
 	// SettingsView is the view for configuring settings
 	SettingsView
+	// NotesView is the view for editing the notes of a task
+	NotesView
+	// ArchiveBrowserView is the view for browsing archived and trashed tasks
+	ArchiveBrowserView
+	// StatisticsView is the view showing productivity statistics
+	StatisticsView
+	// HeatmapCalendarView is the view showing a year of focus time as a calendar heatmap
+	HeatmapCalendarView
+	// EstimationView is the view showing how estimates compare to the pomodoros spent
+	EstimationView
+	// WorkspaceSwitcherView is the view for switching between workspaces
+	WorkspaceSwitcherView
 )
 
 // TickMsg is sent when the timer should update
 type TickMsg time.Time
 
+// reminderCommandMsg is sent when the notification command of a reminder has finished
+type reminderCommandMsg struct {
+	err error
+}
+
+// sessionCommitsMsg is sent when the commits made during a focus session have been looked up
+type sessionCommitsMsg struct {
+	sessionID string
+	commits   []model.Commit
+	err       error
+}
+
+// reminderDisplayTime is how long a reminder is shown over the main view
+const reminderDisplayTime = 20 * time.Second
+
 // WindowSizeMsg is sent when the terminal window size changes
 type WindowSizeMsg struct {
 	Width  int
@@ -51,24 +82,65 @@ type App struct {
 	timer           *model.Timer
 	taskManager     *model.TaskManager
 	settingsManager *model.SettingsManager
+	history         *model.History
 	storageManager  *storage.StorageManager
 	view            ViewState
 	width           int
 	height          int
 
+	// Where data is stored, including the workspace in use
+	options storage.Options
+
+	// Works out when reminders are due during focus periods
+	reminderScheduler *model.ReminderScheduler
+	// Reminder shown over the main view until reminderUntil
+	reminder      string
+	reminderUntil time.Time
+
+	// Commit lookups for recorded focus sessions, started on the next tick
+	commitLookups []tea.Cmd
+
 	// Input fields for adding tasks
 	taskInput      textinput.Model
 	pomodorosInput textinput.Model
 	inputting      bool
+	// ID of the parent task when adding a subtask (empty for top-level tasks)
+	addParentID string
+
+	// Search input for filtering the task list
+	searchInput textinput.Model
+	searching   bool
+	searchError string
+
+	// Multi-line input for editing task notes
+	notesInput textarea.Model
+	// ID of the task whose notes are being edited
+	notesTaskID string
 
 	// Input fields for settings
 	pomodoroDurationInput   textinput.Model
 	shortBreakDurationInput textinput.Model
 	longBreakDurationInput  textinput.Model
+	trashRetentionInput     textinput.Model
+	dailyGoalInput          textinput.Model
+	flowtimeRatioInput      textinput.Model
+	autoStartGraceInput     textinput.Model
+	restDaysInput           textinput.Model
+	profilesInput           textinput.Model
+	breakActivitiesInput    textinput.Model
+	remindersInput          textinput.Model
+	reminderCommandInput    textinput.Model
 
 	// Components
-	timerView    *TimerView
-	taskListView *TaskListView
+	timerView      *TimerView
+	taskListView   *TaskListView
+	taskDetailView *TaskDetailView
+	archiveView    *ArchiveView
+	statsView      *StatsView
+	estimatesView  *EstimatesView
+	goalView       *GoalView
+	heatmapView    *HeatmapView
+	workspaceView  *WorkspaceView
 
 	// Debug mode
 	debugMode DebugMode
@@ -80,11 +152,11 @@ type App struct {
 	showHelpText bool
 }
 
-// NewApp creates a new application model
-func NewApp() *App {
+// NewApp creates a new application model using the storage selected by the options
+func NewApp(options storage.Options) *App {
 	// Initialize task inputs
 	taskInput := textinput.New()
-	taskInput.Placeholder = "Task description"
+	taskInput.Placeholder = "Task description (supports project:name +tag due:YYYY-MM-DD every:daily)"
 	taskInput.Width = 60
 	taskInput.Focus()
 
@@ -92,6 +164,19 @@ func NewApp() *App {
 	pomodorosInput.Placeholder = "Number of pomodoros (default: 4)"
 	pomodorosInput.Width = 10
 
+	// Initialize search input
+	searchInput := textinput.New()
+	searchInput.Prompt = "/ "
+	searchInput.Placeholder = "fuzzy text, project:name +tag !done due<=today"
+	searchInput.Width = 60
+
+	// Initialize notes input
+	notesInput := textarea.New()
+	notesInput.Placeholder = "Notes (Markdown), e.g. the next step when you resume"
+	notesInput.SetWidth(70)
+	notesInput.SetHeight(12)
+	notesInput.ShowLineNumbers = false
+
 	// Initialize settings inputs
 	pomodoroDurationInput := textinput.New()
 	pomodoroDurationInput.Placeholder = "Pomodoro duration (minutes)"
@@ -105,24 +190,60 @@ func NewApp() *App {
 	longBreakDurationInput.Placeholder = "Long break duration (minutes)"
 	longBreakDurationInput.Width = 10
 
+	trashRetentionInput := textinput.New()
+	trashRetentionInput.Placeholder = "Days to keep trashed tasks (0 = forever)"
+	trashRetentionInput.Width = 10
+
+	dailyGoalInput := textinput.New()
+	dailyGoalInput.Placeholder = "Pomodoros per day (0 = no goal)"
+	dailyGoalInput.Width = 10
+
+	flowtimeRatioInput := textinput.New()
+	flowtimeRatioInput.Placeholder = "Focus minutes per break minute"
+	flowtimeRatioInput.Width = 10
+
+	autoStartGraceInput := textinput.New()
+	autoStartGraceInput.Placeholder = "Seconds before a pomodoro starts automatically"
+	autoStartGraceInput.Width = 10
+
+	restDaysInput := textinput.New()
+	restDaysInput.Placeholder = "e.g. sat,sun"
+	restDaysInput.Width = 30
+
+	profilesInput := textinput.New()
+	profilesInput.Placeholder = "e.g. classic 25/5/15; deep work 50/10/30 project:api +writing"
+	profilesInput.Width = 60
+
+	breakActivitiesInput := textinput.New()
+	breakActivitiesInput.Placeholder = "e.g. Stretch; Take a short walk; Get a glass of water"
+	breakActivitiesInput.Width = 60
+
+	remindersInput := textinput.New()
+	remindersInput.Placeholder = "e.g. 20 Look away for 20 seconds; 60 Drink some water"
+	remindersInput.Width = 60
+
+	reminderCommandInput := textinput.New()
+	reminderCommandInput.Placeholder = "e.g. notify-send Pomodoro (empty for none)"
+	reminderCommandInput.Width = 60
+
 	width := GetTerminalWidth()
 	height := GetTerminalHeight()
 
 	// Initialize model objects
 	settingsManager := model.NewSettingsManager()
 	taskManager := model.NewTaskManager()
+	history := model.NewHistory()
 	timer := model.NewTimer(taskManager)
 
 	// Set the timer to use the settings
 	timer.SetSettings(&settingsManager.Settings)
 
-	// Initialize storage
-	jsonStorage, err := storage.NewJSONTaskStorage("./data/tasks.json")
-	var storageManager *storage.StorageManager
-	if err == nil {
-		// Now jsonStorage implements both TaskStorage and SettingsStorage
-		storageManager = storage.NewStorageManager(jsonStorage, jsonStorage, taskManager, &settingsManager.Settings)
+	// Record sessions to the history
+	timer.SetHistory(history)
 
+	// Initialize storage; session history lives in its own file next to the tasks
+	storageManager, err := storage.OpenStorageManager(options, taskManager, &settingsManager.Settings, history)
+	if err == nil {
 		// Load tasks from storage
 		if err := storageManager.LoadTasks(); err != nil {
 			// If loading fails, we'll start with an empty task list
@@ -141,6 +262,22 @@ func NewApp() *App {
 			// Explicitly reset the timer to ensure it uses the loaded duration
 			timer.Reset()
 		}
+
+		// Load session history from storage
+		if err := storageManager.LoadHistory(); err != nil {
+			// If loading fails, keep sessions in memory only so the file isn't overwritten
+			fmt.Println("Error loading history:", err)
+		} else {
+			// Save the history whenever a session is recorded
+			history.RegisterRecordHandler(storageManager.AutoSaveHistory())
+		}
+
+		// Permanently delete tasks that have been in the trash past the retention period
+		if taskManager.PurgeTrash(settingsManager.Settings.GetTrashRetention(), time.Now()) > 0 {
+			if err := storageManager.SaveTasks(); err != nil {
+				fmt.Println("Error saving tasks after purging trash:", err)
+			}
+		}
 	}
 
 	// Initialize the font manager
@@ -155,24 +292,55 @@ func NewApp() *App {
 		timer:                   timer,
 		taskManager:             taskManager,
 		settingsManager:         settingsManager,
+		history:                 history,
 		storageManager:          storageManager,
+		options:                 options,
 		view:                    MainView,
 		width:                   width,
 		height:                  height,
 		taskInput:               taskInput,
 		pomodorosInput:          pomodorosInput,
+		notesInput:              notesInput,
+		searchInput:             searchInput,
 		pomodoroDurationInput:   pomodoroDurationInput,
 		shortBreakDurationInput: shortBreakDurationInput,
 		longBreakDurationInput:  longBreakDurationInput,
+		trashRetentionInput:     trashRetentionInput,
+		dailyGoalInput:          dailyGoalInput,
+		flowtimeRatioInput:      flowtimeRatioInput,
+		autoStartGraceInput:     autoStartGraceInput,
+		restDaysInput:           restDaysInput,
+		profilesInput:           profilesInput,
+		breakActivitiesInput:    breakActivitiesInput,
+		remindersInput:          remindersInput,
+		reminderCommandInput:    reminderCommandInput,
+		reminderScheduler:       model.NewReminderScheduler(),
 		inputting:               false,
 		debugMode:               NoDebug,
 		fontManager:             fontManager,
 		showHelpText:            false, // Show help text by default
 	}
 
+	// Attach the commits made during focus sessions on tasks linked to a git repository
+	history.RegisterBeforeRecordHandler(app.queueCommitLookup)
+
 	// Initialize components
 	app.timerView = NewTimerView(timer, width)
 	app.taskListView = NewTaskListView(taskManager, width)
+	app.taskDetailView = NewTaskDetailView(width)
+	app.archiveView = NewArchiveView(taskManager, &settingsManager.Settings, width)
+	app.statsView = NewStatsView(history, &settingsManager.Settings, width)
+	app.estimatesView = NewEstimatesView(taskManager, width)
+	app.goalView = NewGoalView(history, &settingsManager.Settings, width)
+	app.heatmapView = NewHeatmapView(history, width)
+	app.workspaceView = NewWorkspaceView(width)
+
+	// Offer the workspace in the switcher from now on
+	if options.Workspace != nil && options.WorkspacesPath != "" {
+		if err := storage.RememberWorkspace(options.WorkspacesPath, options.Workspace, time.Now()); err != nil {
+			fmt.Println("Error recording workspace:", err)
+		}
+	}
 
 	// Set the font manager in the timer view
 	if fontManager != nil {
@@ -187,9 +355,9 @@ func NewApp() *App {
 		// Explicitly reset the timer when settings change
 		timer.Reset()
 
-		// Save settings on change
-		if storageManager != nil {
-			_ = storageManager.SaveSettings()
+		// Save settings on change, to the storage of the workspace in use
+		if app.storageManager != nil {
+			_ = app.storageManager.SaveSettings()
 		}
 	})
 
@@ -198,8 +366,9 @@ func NewApp() *App {
 
 // Init initializes the Bubble Tea program
 func (a *App) Init() tea.Cmd {
-	// Only add sample tasks if we don't have any (i.e., no tasks were loaded from storage)
-	if len(a.taskManager.GetTasks()) == 0 {
+	// Only add sample tasks if we don't have any (i.e., no tasks were loaded from storage),
+	// and not to a project's workspace
+	if len(a.taskManager.GetTasks()) == 0 && a.options.Workspace == nil {
 		// Add some sample tasks for demonstration
 		a.taskManager.AddTask("Work on design concept", 4)
 		a.taskManager.AddTask("Test the prototype with users", 3)
@@ -262,9 +431,35 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		// Continue ticking
-		return a, tea.Tick(time.Second, func(t time.Time) tea.Msg {
+		cmds := []tea.Cmd{tea.Tick(time.Second, func(t time.Time) tea.Msg {
 			return TickMsg(t)
-		})
+		})}
+
+		// Show the reminders that came due, also passing them to the notification command
+		for _, reminder := range a.reminderScheduler.Due(a.timer, a.settingsManager.Settings.Reminders) {
+			a.showReminder(reminder.Message)
+			if command := a.settingsManager.Settings.ReminderCommand; command != "" {
+				cmds = append(cmds, runReminderCommand(command, reminder.Message))
+			}
+		}
+
+		// Look up the commits of the sessions recorded since the last tick
+		cmds = append(cmds, a.commitLookups...)
+		a.commitLookups = nil
+		return a, tea.Batch(cmds...)
+
+	case sessionCommitsMsg:
+		// A repository that moved, a missing git binary or a slow git just leaves the session without commits
+		if msg.err == nil && len(msg.commits) > 0 {
+			a.history.AttachCommits(msg.sessionID, msg.commits)
+		}
+		return a, nil
+
+	case reminderCommandMsg:
+		if msg.err != nil {
+			a.showReminder("Reminder command failed: " + msg.err.Error())
+		}
+		return a, nil
 
 	case tea.KeyMsg:
 		switch a.view {
@@ -283,6 +478,18 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return a.updateAddTaskView(msg)
 		case SettingsView:
 			return a.updateSettingsView(msg)
+		case NotesView:
+			return a.updateNotesView(msg)
+		case ArchiveBrowserView:
+			return a.updateArchiveView(msg)
+		case StatisticsView:
+			return a.updateStatsView(msg)
+		case HeatmapCalendarView:
+			return a.updateHeatmapView(msg)
+		case EstimationView:
+			return a.updateEstimatesView(msg)
+		case WorkspaceSwitcherView:
+			return a.updateWorkspaceView(msg)
 		}
 	}
 
@@ -291,6 +498,11 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // updateMainView handles input for the main view
 func (a *App) updateMainView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// While searching, keys go to the search input
+	if a.searching {
+		return a.updateSearch(msg)
+	}
+
 	switch msg.String() {
 	case "ctrl+c", "q":
 		return a, tea.Quit
@@ -305,19 +517,51 @@ func (a *App) updateMainView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 
 	case "R", "r":
-		// Reset timer to full duration
-		a.timer.Reset()
+		// Reset timer to full duration, which would cut a break short in strict mode
+		if a.timer.BreakLocked() && a.timer.State != model.TimerStopped {
+			a.timer.NoteSkipAttempt()
+		} else {
+			a.timer.Reset()
+		}
 
 	case "B", "b":
-		// Skip the current break
-		a.timer.SkipBreak()
+		// End a Flowtime focus with the break it earned, or skip the current break
+		if a.timer.FinishFlow() {
+			// Save the pomodoro and time credited to the task
+			if a.storageManager != nil {
+				if err := a.storageManager.SaveTasks(); err != nil {
+					fmt.Println("Error saving tasks:", err)
+				}
+			}
+		} else {
+			a.timer.SkipBreak()
+		}
 
 	case "N", "n":
 		// Add new task
 		a.view = AddTaskView
+		a.addParentID = ""
 		a.taskInput.Focus()
 		a.inputting = true
 
+	case "A", "a":
+		// Add a subtask to the selected task
+		if selectedTaskPtr := a.taskListView.GetSelectedTaskPtr(); selectedTaskPtr != nil {
+			a.view = AddTaskView
+			a.addParentID = selectedTaskPtr.ID
+			a.taskInput.Focus()
+			a.inputting = true
+		}
+
+	case "C", "c":
+		// Expand or collapse the subtasks of the selected task
+		a.taskListView.ToggleSelectedCollapsed()
+		if a.storageManager != nil {
+			if err := a.storageManager.SaveTasks(); err != nil {
+				fmt.Println("Error saving tasks:", err)
+			}
+		}
+
 	case "H", "h":
 		// Toggle hiding completed tasks
 		a.taskListView.ToggleShowCompleted()
@@ -331,22 +575,43 @@ func (a *App) updateMainView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		a.taskListView.MoveSelectionUp()
 
 	case "enter":
+		// Parent tasks accrue totals from their subtasks, so Enter just expands or collapses them
+		if node, ok := a.taskListView.GetSelectedNode(); ok && node.HasChildren {
+			a.taskListView.ToggleSelectedCollapsed()
+			if a.storageManager != nil {
+				if err := a.storageManager.SaveTasks(); err != nil {
+					fmt.Println("Error saving tasks:", err)
+				}
+			}
+			return a, nil
+		}
+
 		// Select current task
 		if selectedTaskPtr := a.taskListView.GetSelectedTaskPtr(); selectedTaskPtr != nil {
+			a.applyTaskProfile(*selectedTaskPtr)
 			a.timer.SetCurrentTask(selectedTaskPtr.ID)
 			// Update task list view with current task
 			a.taskListView.SetCurrentTask(selectedTaskPtr)
-			a.timer.Start()
+			// A running pomodoro carries on with the new task, its time split between the two
+			if a.timer.State != model.TimerRunning {
+				a.timer.Start()
+			}
 		}
 
 	case " ":
 		// Toggle task complete
 		a.taskListView.ToggleSelectedTaskComplete()
+		// Move on to the next task in the queue once the current one is done, unless a pomodoro
+		// is under way on it
+		if !(a.timer.Mode == model.FocusMode && a.timer.State != model.TimerStopped) {
+			a.timer.AdvanceQueue()
+		}
 
 	case "D", "d":
-		// Delete the selected task
+		// Move the selected task to the trash
 		if selectedTaskPtr := a.taskListView.GetSelectedTaskPtr(); selectedTaskPtr != nil {
-			a.taskListView.DeleteSelectedTask()
+			a.taskListView.TrashSelectedTask()
+			a.clearUnlistedCurrentTask()
 			// Save tasks after deletion
 			if a.storageManager != nil {
 				if err := a.storageManager.SaveTasks(); err != nil {
@@ -355,6 +620,81 @@ func (a *App) updateMainView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+	case "X", "x":
+		// Archive the selected task if it is completed
+		if a.taskListView.ArchiveSelectedTask() {
+			a.clearUnlistedCurrentTask()
+			if a.storageManager != nil {
+				if err := a.storageManager.SaveTasks(); err != nil {
+					fmt.Println("Error saving tasks:", err)
+				}
+			}
+		}
+
+	case "V", "v":
+		// Browse archived and trashed tasks
+		a.view = ArchiveBrowserView
+
+	case "T", "t":
+		// Show productivity statistics
+		a.view = StatisticsView
+
+	case "Y", "y":
+		// Show the year of focus time as a calendar heatmap
+		a.view = HeatmapCalendarView
+
+	case "I", "i":
+		// Show estimation accuracy
+		a.view = EstimationView
+
+	case "+", "=", "-":
+		// Re-estimate the selected task
+		delta := 1
+		if msg.String() == "-" {
+			delta = -1
+		}
+		if a.taskListView.ReestimateSelectedTask(delta) {
+			// Save tasks after re-estimating
+			if a.storageManager != nil {
+				if err := a.storageManager.SaveTasks(); err != nil {
+					fmt.Println("Error saving tasks:", err)
+				}
+			}
+		}
+
+	case "E", "e":
+		// Edit the notes of the selected task
+		if selectedTaskPtr := a.taskListView.GetSelectedTaskPtr(); selectedTaskPtr != nil {
+			a.view = NotesView
+			a.notesTaskID = selectedTaskPtr.ID
+			a.notesInput.SetValue(selectedTaskPtr.Notes)
+			a.notesInput.Focus()
+			return a, textarea.Blink
+		}
+
+	case "G", "g":
+		// Link the selected task to the git branch checked out in the working directory, or unlink it
+		if selectedTaskPtr := a.taskListView.GetSelectedTaskPtr(); selectedTaskPtr != nil {
+			if a.toggleGitBranch(selectedTaskPtr.ID) && a.storageManager != nil {
+				if err := a.storageManager.SaveTasks(); err != nil {
+					fmt.Println("Error saving tasks:", err)
+				}
+			}
+		}
+
+	case "W", "w":
+		// Switch to another workspace
+		a.workspaceView.Reload(a.options.WorkspacesPath, a.workspaceRoot())
+		a.view = WorkspaceSwitcherView
+
+	case "P", "p":
+		// Switch to the next profile; applying one resets the timer, so not while a pomodoro is under way
+		if a.timer.State == model.TimerStopped {
+			if profile, ok := a.settingsManager.Settings.NextProfile(); ok {
+				a.settingsManager.ApplyProfile(profile)
+			}
+		}
+
 	case "O", "o":
 		// Open settings
 		a.view = SettingsView
@@ -369,6 +709,26 @@ func (a *App) updateMainView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			a.fontManager.NextFont()
 		}
 
+	case "/":
+		// Start searching, editing the active filter if there is one
+		a.searching = true
+		a.searchError = ""
+		a.searchInput.SetValue(a.taskManager.Filter.String())
+		a.searchInput.CursorEnd()
+		a.searchInput.Focus()
+		return a, textinput.Blink
+
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		// Recall a saved filter
+		slot := int(msg.String()[0] - '0')
+		if expression, ok := a.settingsManager.SavedFilter(slot); ok {
+			a.applyFilter(expression)
+		}
+
+	case "0":
+		// Clear the active filter
+		a.applyFilter("")
+
 	case "?":
 		// Toggle help text visibility
 		a.showHelpText = !a.showHelpText
@@ -377,6 +737,96 @@ func (a *App) updateMainView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return a, nil
 }
 
+// updateSearch handles input while the search bar is active, filtering the list live
+func (a *App) updateSearch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg.String() {
+	case "ctrl+c":
+		return a, tea.Quit
+
+	case "esc":
+		// Clear the filter and leave search mode
+		a.applyFilter("")
+		a.stopSearching()
+		return a, nil
+
+	case "enter":
+		// Keep the filter and leave search mode
+		a.stopSearching()
+		return a, nil
+
+	case "ctrl+s":
+		// Save the current expression for recall with the number keys
+		if a.settingsManager.AddSavedFilter(a.searchInput.Value()) && a.storageManager != nil {
+			_ = a.storageManager.SaveSettings()
+		}
+		return a, nil
+
+	case "ctrl+d":
+		// Remove the current expression from the saved filters
+		if a.settingsManager.RemoveSavedFilter(a.searchInput.Value()) && a.storageManager != nil {
+			_ = a.storageManager.SaveSettings()
+		}
+		return a, nil
+	}
+
+	a.searchInput, cmd = a.searchInput.Update(msg)
+	a.applyFilter(a.searchInput.Value())
+	return a, cmd
+}
+
+// applyFilter sets the task list filter, keeping the previous one if the expression is invalid
+func (a *App) applyFilter(expression string) {
+	if err := a.taskManager.SetFilter(expression); err != nil {
+		a.searchError = err.Error()
+		return
+	}
+	a.searchError = ""
+	a.taskListView.ResetSelection()
+}
+
+// stopSearching leaves search mode
+func (a *App) stopSearching() {
+	a.searching = false
+	a.searchError = ""
+	a.searchInput.Blur()
+}
+
+// renderSearchBar renders the search input, or the active filter, above the task list
+func (a *App) renderSearchBar() string {
+	hintStyle := lipgloss.NewStyle().Foreground(ColorGrayText)
+
+	var lines []string
+	if a.searching {
+		lines = append(lines, a.searchInput.View())
+		if a.searchError != "" {
+			lines = append(lines, lipgloss.NewStyle().Foreground(ColorStopButton).Render(a.searchError))
+		}
+		lines = append(lines, hintStyle.Render("[Enter] Keep  [Esc] Clear  [Ctrl+S] Save filter  [Ctrl+D] Delete saved filter"))
+	} else if !a.taskManager.Filter.Empty() {
+		lines = append(lines, HideCompletedStyle.Render("Filter: ")+a.taskManager.Filter.String()+
+			hintStyle.Render("   [/] Edit  [0] Clear"))
+	}
+
+	// List saved filters with their recall keys
+	if len(a.settingsManager.Settings.SavedFilters) > 0 && (a.searching || !a.taskManager.Filter.Empty()) {
+		saved := make([]string, len(a.settingsManager.Settings.SavedFilters))
+		for i, expression := range a.settingsManager.Settings.SavedFilters {
+			saved[i] = fmt.Sprintf("[%d] %s", i+1, expression)
+		}
+		lines = append(lines, hintStyle.Render(strings.Join(saved, "  ")))
+	}
+
+	if len(lines) == 0 {
+		return ""
+	}
+	return lipgloss.NewStyle().
+		Padding(0, 2, 1, 2).
+		Width(a.width - 40).
+		Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
+
 // updateAddTaskView handles input for the add task view
 func (a *App) updateAddTaskView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
@@ -392,6 +842,7 @@ func (a *App) updateAddTaskView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		a.pomodorosInput.Blur()
 		a.taskInput.SetValue("")
 		a.pomodorosInput.SetValue("")
+		a.addParentID = ""
 		return a, nil
 
 	case "?": // Toggle help text visibility
@@ -411,8 +862,13 @@ func (a *App) updateAddTaskView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "enter":
 		// Submit new task
 		if a.taskInput.Value() != "" {
-			description := strings.TrimSpace(a.taskInput.Value())
+			// Split out project:name, +tag and due:date tokens
+			description, attrs := model.ParseTaskInput(strings.TrimSpace(a.taskInput.Value()))
 			pomodoros := 4 // Default
+			if suggestion, ok := a.taskManager.SuggestEstimate(a.taskInput.Value()); ok {
+				// Default to what similar tasks actually took
+				pomodoros = suggestion.Pomodoros
+			}
 
 			// Try to parse the pomodoros input
 			if a.pomodorosInput.Value() != "" {
@@ -422,7 +878,19 @@ func (a *App) updateAddTaskView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				}
 			}
 
-			task := a.taskManager.AddTask(description, pomodoros)
+			var task model.Task
+			if a.addParentID != "" {
+				// Add as a subtask, falling back to a top-level task if the parent is gone
+				subtask, ok := a.taskManager.AddSubtask(a.addParentID, description, pomodoros)
+				if ok {
+					task = subtask
+				} else {
+					task = a.taskManager.AddTask(description, pomodoros)
+				}
+			} else {
+				task = a.taskManager.AddTask(description, pomodoros)
+			}
+			a.taskManager.SetAttributes(task.ID, attrs)
 			a.timer.SetCurrentTask(task.ID)
 
 			// Save tasks after adding a new one
@@ -434,6 +902,7 @@ func (a *App) updateAddTaskView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 			a.view = MainView
 			a.inputting = false
+			a.addParentID = ""
 			a.taskInput.Reset()
 			a.pomodorosInput.Reset()
 		}
@@ -451,119 +920,593 @@ func (a *App) updateAddTaskView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return a, nil
 }
 
-// updateSettingsView handles input for the settings view
-func (a *App) updateSettingsView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+// updateNotesView handles input for the notes editor view
+func (a *App) updateNotesView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
-	// First, handle special keys that should always work regardless of focus
+	switch msg.String() {
+	case "ctrl+c":
+		return a, tea.Quit
+
+	case "esc":
+		// Cancel and return to main view without saving
+		a.closeNotesView()
+		return a, nil
+
+	case "ctrl+s":
+		// Save the notes and return to main view
+		a.taskManager.SetNotes(a.notesTaskID, a.notesInput.Value())
+		if a.storageManager != nil {
+			if err := a.storageManager.SaveTasks(); err != nil {
+				fmt.Println("Error saving tasks:", err)
+			}
+		}
+		a.closeNotesView()
+		return a, nil
+	}
+
+	// Handle textarea updates
+	a.notesInput, cmd = a.notesInput.Update(msg)
+	return a, cmd
+}
+
+// closeNotesView leaves the notes editor and returns to the main view
+func (a *App) closeNotesView() {
+	a.view = MainView
+	a.notesTaskID = ""
+	a.notesInput.Blur()
+	a.notesInput.Reset()
+}
+
+// updateArchiveView handles input for the archive and trash browser
+func (a *App) updateArchiveView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	changed := false
+
 	switch msg.String() {
 	case "ctrl+c", "q":
 		return a, tea.Quit
 
-	case "esc", "o":
-		// Save settings on exit
-		a.saveSettings()
+	case "esc", "v", "V":
 		// Return to main view
 		a.view = MainView
 		return a, nil
 
-	case "a", "A":
-		// Always handle 'a' key for toggling auto-start breaks
-		// Toggle auto-start breaks
-		a.settingsManager.Settings.AutoStartBreaks = !a.settingsManager.Settings.AutoStartBreaks
-		// Save settings after toggling
-		a.saveSettings()
-		return a, nil
+	case "tab", "shift+tab":
+		// Switch between the archive and the trash
+		a.archiveView.ToggleTab()
+
+	case "J", "j", "down":
+		a.archiveView.MoveSelectionDown()
+
+	case "K", "k", "up":
+		a.archiveView.MoveSelectionUp()
+
+	case "R", "r":
+		// Restore the selected task to the task list
+		changed = a.archiveView.RestoreSelectedTask()
+
+	case "D", "d":
+		// Trash an archived task, or delete a trashed task forever
+		changed = a.archiveView.DeleteSelectedTask()
+		a.clearUnlistedCurrentTask()
+
+	case "E", "e":
+		// Empty the trash
+		if a.archiveView.ShowingTrash() {
+			changed = a.archiveView.EmptyTrash() > 0
+			a.clearUnlistedCurrentTask()
+		}
 
 	case "?":
 		// Toggle help text visibility
 		a.showHelpText = !a.showHelpText
-		return a, nil
 	}
 
-	// Then handle navigation keys
-	switch msg.String() {
-	case "tab", "shift+tab":
-		// Switch between inputs
-		if a.pomodoroDurationInput.Focused() {
-			a.pomodoroDurationInput.Blur()
-			a.shortBreakDurationInput.Focus()
-		} else if a.shortBreakDurationInput.Focused() {
-			a.shortBreakDurationInput.Blur()
-			a.longBreakDurationInput.Focus()
-		} else {
-			a.longBreakDurationInput.Blur()
-			a.pomodoroDurationInput.Focus()
+	if changed && a.storageManager != nil {
+		if err := a.storageManager.SaveTasks(); err != nil {
+			fmt.Println("Error saving tasks:", err)
 		}
-		return a, nil
+	}
 
-	case "enter":
-		// Save settings using the saveSettings method
-		a.saveSettings()
+	return a, nil
+}
 
-		// Return to main view after saving
+// updateStatsView handles input for the stats view
+func (a *App) updateStatsView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return a, tea.Quit
+
+	case "esc", "t", "T":
+		// Return to main view
 		a.view = MainView
-		a.pomodoroDurationInput.Blur()
-		a.shortBreakDurationInput.Blur()
-		a.longBreakDurationInput.Blur()
-		return a, nil
+
+	case "+", "=", "right", "l", "L":
+		// Show one more week
+		a.statsView.MoreWeeks()
+
+	case "-", "left", "h", "H":
+		// Show one week less
+		a.statsView.FewerWeeks()
+
+	case "?":
+		// Toggle help text visibility
+		a.showHelpText = !a.showHelpText
 	}
 
-	// Then handle navigation keys
+	return a, nil
+}
+
+// updateEstimatesView handles input for the estimation accuracy view
+func (a *App) updateEstimatesView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
-	case "tab", "down", "j", "J":
-		// Move to next input field
-		if a.pomodoroDurationInput.Focused() {
-			a.pomodoroDurationInput.Blur()
-			a.shortBreakDurationInput.Focus()
-		} else if a.shortBreakDurationInput.Focused() {
-			a.shortBreakDurationInput.Blur()
-			a.longBreakDurationInput.Focus()
-		} else {
-			a.longBreakDurationInput.Blur()
-			a.pomodoroDurationInput.Focus()
-		}
-		return a, nil
+	case "ctrl+c", "q":
+		return a, tea.Quit
 
-	case "up", "k", "K", "shift+tab":
-		// Move to previous input field
-		if a.pomodoroDurationInput.Focused() {
-			a.pomodoroDurationInput.Blur()
-			a.longBreakDurationInput.Focus()
-		} else if a.shortBreakDurationInput.Focused() {
-			a.shortBreakDurationInput.Blur()
-			a.pomodoroDurationInput.Focus()
-		} else {
-			a.longBreakDurationInput.Blur()
-			a.shortBreakDurationInput.Focus()
-		}
-		return a, nil
-	}
+	case "esc", "i", "I":
+		// Return to main view
+		a.view = MainView
 
-	// Finally handle text input updates
-	if a.pomodoroDurationInput.Focused() {
-		a.pomodoroDurationInput, cmd = a.pomodoroDurationInput.Update(msg)
-		return a, cmd
-	} else if a.shortBreakDurationInput.Focused() {
-		a.shortBreakDurationInput, cmd = a.shortBreakDurationInput.Update(msg)
-		return a, cmd
-	} else if a.longBreakDurationInput.Focused() {
-		a.longBreakDurationInput, cmd = a.longBreakDurationInput.Update(msg)
-		return a, cmd
+	case "+", "=", "right", "l", "L":
+		// Show one more week
+		a.estimatesView.MoreWeeks()
+
+	case "-", "left", "h", "H":
+		// Show one week less
+		a.estimatesView.FewerWeeks()
+
+	case "?":
+		// Toggle help text visibility
+		a.showHelpText = !a.showHelpText
 	}
 
 	return a, nil
 }
 
-// updateSettingsInputs updates the input fields with current settings values
-func (a *App) updateSettingsInputs() {
-	a.pomodoroDurationInput.SetValue(fmt.Sprintf("%d", a.settingsManager.Settings.PomodoroDuration))
-	a.shortBreakDurationInput.SetValue(fmt.Sprintf("%d", a.settingsManager.Settings.ShortBreakDuration))
-	a.longBreakDurationInput.SetValue(fmt.Sprintf("%d", a.settingsManager.Settings.LongBreakDuration))
-}
+// updateWorkspaceView handles input for the workspace switcher
+func (a *App) updateWorkspaceView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return a, tea.Quit
 
-// View renders the current UI
-func (a *App) View() string {
+	case "esc", "w", "W":
+		// Return to main view
+		a.view = MainView
+
+	case "J", "j", "down":
+		a.workspaceView.MoveSelectionDown()
+
+	case "K", "k", "up":
+		a.workspaceView.MoveSelectionUp()
+
+	case "enter":
+		// Switch to the selected workspace and return to the main view
+		if entry, ok := a.workspaceView.GetSelected(); ok && entry.Root != a.workspaceRoot() {
+			if err := a.switchWorkspace(entry.Root); err != nil {
+				fmt.Println("Error switching workspace:", err)
+			}
+		}
+		a.view = MainView
+
+	case "?":
+		// Toggle help text visibility
+		a.showHelpText = !a.showHelpText
+	}
+
+	return a, nil
+}
+
+// applyTaskProfile switches to the profile set for a task's project or tags, unless a pomodoro is under way
+func (a *App) applyTaskProfile(task model.Task) {
+	if a.timer.State != model.TimerStopped {
+		return
+	}
+	settings := a.settingsManager.Settings
+	profile, ok := settings.ProfileForTask(a.taskManager.EffectiveProject(task.ID), a.taskManager.EffectiveTags(task.ID))
+	if ok && !profile.Matches(settings) {
+		a.settingsManager.ApplyProfile(profile)
+	}
+}
+
+// profileLine names the profile whose durations are in use and any durations the current task
+// sets instead, or returns "" if there are neither
+func (a *App) profileLine() string {
+	settings := a.settingsManager.Settings
+	parts := make([]string, 0, 2)
+	if len(settings.Profiles) > 0 {
+		if profile, ok := settings.ActiveProfile(); ok {
+			parts = append(parts, "profile: "+profile.Name+" "+profile.Durations()+"  [P] Switch")
+		} else {
+			parts = append(parts, fmt.Sprintf("profile: custom %d/%d/%d  [P] Switch",
+				settings.PomodoroDuration, settings.ShortBreakDuration, settings.LongBreakDuration))
+		}
+	}
+	if a.timer.CurrentTaskID != "" {
+		if durations := a.taskManager.EffectiveDurations(a.timer.CurrentTaskID); durations != (model.TaskDurations{}) {
+			parts = append(parts, "task timer: "+durations.String())
+		}
+	}
+	return strings.Join(parts, "  ·  ")
+}
+
+// showReminder shows a reminder over the main view for a while
+func (a *App) showReminder(message string) {
+	a.reminder = message
+	a.reminderUntil = time.Now().Add(reminderDisplayTime)
+}
+
+// queueCommitLookup queues looking up the commits made during a focus session on a task linked
+// to a git repository. The lookup runs in the background from the next tick, so that git can't
+// hold up the timer.
+func (a *App) queueCommitLookup(session *model.Session) {
+	if !session.IsFocus() || session.TaskID == "" {
+		return
+	}
+	task, found := a.taskManager.GetTask(session.TaskID)
+	if !found || task.GitRepo == "" {
+		return
+	}
+
+	sessionID, repo, start, end := session.ID, task.GitRepo, session.Start, session.End
+	a.commitLookups = append(a.commitLookups, func() tea.Msg {
+		commits, err := interop.GitCommits(repo, start, end)
+		return sessionCommitsMsg{sessionID: sessionID, commits: commits, err: err}
+	})
+}
+
+// runReminderCommand runs the notification command of a reminder in the background
+func runReminderCommand(command, message string) tea.Cmd {
+	return func() tea.Msg {
+		return reminderCommandMsg{err: interop.RunNotifyCommand(command, message)}
+	}
+}
+
+// reminderOverlay renders the reminder being shown, or returns "" if there is none. Reminders
+// disappear once they expire or the focus period ends.
+func (a *App) reminderOverlay() string {
+	if a.reminder == "" || time.Now().After(a.reminderUntil) || a.timer.Mode != model.FocusMode {
+		return ""
+	}
+	return lipgloss.PlaceHorizontal(a.width-10, lipgloss.Center,
+		lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(ColorTaskTag).
+			Foreground(ColorText).
+			Bold(true).
+			Padding(0, 2).
+			Render("⏰ "+a.reminder))
+}
+
+// workspaceRoot returns the root of the workspace in use (empty for the global task list)
+func (a *App) workspaceRoot() string {
+	if a.options.Workspace == nil {
+		return ""
+	}
+	return a.options.Workspace.Root
+}
+
+// switchWorkspace saves the tasks of the workspace in use and loads the tasks and settings of
+// the workspace in root, or the global ones if root is empty. The history is shared by all.
+func (a *App) switchWorkspace(root string) error {
+	options := a.options
+	options.Workspace = nil
+	// Task files given on the command line belong to the workspace they were opened in
+	options.TodoTxtPath = ""
+	options.MarkdownPath = ""
+	if root != "" {
+		workspace, err := storage.OpenWorkspace(root)
+		if err != nil {
+			return err
+		}
+		options.Workspace = workspace
+	}
+
+	storageManager, err := storage.OpenStorageManager(options, a.taskManager, &a.settingsManager.Settings, a.history)
+	if err != nil {
+		return err
+	}
+	if a.storageManager != nil {
+		if err := a.storageManager.SaveTasks(); err != nil {
+			return err
+		}
+	}
+
+	// Record the session in progress against the task it was for before the tasks change
+	a.timer.Reset()
+	a.timer.SetCurrentTask("")
+	a.taskListView.SetCurrentTask(nil)
+
+	// Keep the tasks and settings of the current workspace if the new one can't be loaded,
+	// so that they are never saved through the wrong storage
+	previousTasks := a.taskManager.GetTasks()
+	previousSettings := a.settingsManager.Settings
+	err = storageManager.LoadTasks()
+	if err == nil {
+		err = storageManager.LoadSettings()
+	}
+	if err != nil {
+		a.taskManager.LoadTasks(previousTasks)
+		a.settingsManager.Settings = previousSettings
+		return err
+	}
+	a.timer.SetSettings(&a.settingsManager.Settings)
+	a.timer.Reset()
+
+	a.storageManager = storageManager
+	a.options = options
+	if options.Workspace != nil && options.WorkspacesPath != "" {
+		return storage.RememberWorkspace(options.WorkspacesPath, options.Workspace, time.Now())
+	}
+	return nil
+}
+
+// updateHeatmapView handles input for the calendar heatmap view
+func (a *App) updateHeatmapView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return a, tea.Quit
+
+	case "esc", "y", "Y":
+		// Return to main view
+		a.view = MainView
+
+	case "left", "h", "H":
+		// Previous week (columns are weeks)
+		a.heatmapView.MoveCursor(-7)
+
+	case "right", "l", "L":
+		// Next week
+		a.heatmapView.MoveCursor(7)
+
+	case "up", "k", "K":
+		// Previous day (rows are weekdays)
+		a.heatmapView.MoveCursor(-1)
+
+	case "down", "j", "J":
+		// Next day
+		a.heatmapView.MoveCursor(1)
+
+	case "[", "pgup":
+		// Previous month
+		a.heatmapView.MoveCursorMonths(-1)
+
+	case "]", "pgdown":
+		// Next month
+		a.heatmapView.MoveCursorMonths(1)
+
+	case "?":
+		// Toggle help text visibility
+		a.showHelpText = !a.showHelpText
+	}
+
+	return a, nil
+}
+
+// clearUnlistedCurrentTask stops tracking the current task if it was archived, trashed or deleted
+func (a *App) clearUnlistedCurrentTask() {
+	if a.timer.CurrentTaskID == "" {
+		return
+	}
+	if task, found := a.taskManager.GetTask(a.timer.CurrentTaskID); !found || !task.IsListed() {
+		a.timer.SetCurrentTask("")
+		a.taskListView.SetCurrentTask(nil)
+	}
+}
+
+// toggleGitBranch links a task to the git branch checked out in the working directory, or
+// unlinks it if it is already linked, and reports whether the task changed
+func (a *App) toggleGitBranch(taskID string) bool {
+	task, found := a.taskManager.GetTask(taskID)
+	if !found {
+		return false
+	}
+	if task.GitRepo != "" {
+		a.taskManager.SetGitBranch(taskID, "", "")
+		return true
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		return false
+	}
+	checkout, err := interop.DetectGitCheckout(dir)
+	if err != nil {
+		return false
+	}
+	a.taskManager.SetGitBranch(taskID, checkout.Root, checkout.Branch)
+	return true
+}
+
+// SelectBranchTask makes the task linked to the git branch checked out in dir the current task,
+// creating one named after the branch if there is none
+func (a *App) SelectBranchTask(dir string) error {
+	checkout, err := interop.DetectGitCheckout(dir)
+	if err != nil {
+		return err
+	}
+
+	task, found := a.taskManager.FindByGitBranch(checkout.Root, checkout.Branch)
+	if !found {
+		task = model.NewTask(interop.BranchTaskDescription(checkout.Branch), 4)
+		task.Project = filepath.Base(checkout.Root)
+		task.GitRepo = checkout.Root
+		task.GitBranch = checkout.Branch
+		a.taskManager.Tasks = append(a.taskManager.Tasks, task)
+
+		if a.storageManager != nil {
+			if err := a.storageManager.SaveTasks(); err != nil {
+				return err
+			}
+		}
+	}
+
+	a.timer.SetCurrentTask(task.ID)
+	a.taskListView.SetCurrentTask(&task)
+	return nil
+}
+
+// updateSettingsView handles input for the settings view
+func (a *App) updateSettingsView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	// Letters typed into the fields that take words go to the field rather than acting as shortcuts
+	if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+		for _, input := range []*textinput.Model{&a.restDaysInput, &a.profilesInput, &a.breakActivitiesInput, &a.remindersInput, &a.reminderCommandInput} {
+			if input.Focused() {
+				*input, cmd = input.Update(msg)
+				return a, cmd
+			}
+		}
+	}
+
+	// First, handle special keys that should always work regardless of focus
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return a, tea.Quit
+
+	case "esc", "o":
+		// Save settings on exit
+		a.saveSettings()
+		// Return to main view
+		a.view = MainView
+		return a, nil
+
+	case "a", "A":
+		// Always handle 'a' key for toggling auto-start breaks
+		// Toggle auto-start breaks
+		a.settingsManager.Settings.AutoStartBreaks = !a.settingsManager.Settings.AutoStartBreaks
+		// Save settings after toggling
+		a.saveSettings()
+		return a, nil
+
+	case "p", "P":
+		// Toggle auto-starting pomodoros after breaks
+		a.settingsManager.SetAutoStartPomodoros(!a.settingsManager.Settings.AutoStartPomodoros)
+		a.saveSettings()
+		return a, nil
+
+	case "f", "F":
+		// Toggle between Flowtime and fixed-length focus periods
+		a.settingsManager.SetFlowtime(!a.settingsManager.Settings.Flowtime)
+		a.saveSettings()
+		return a, nil
+
+	case "t", "T":
+		// Switch to the next rule for crediting pomodoros spent on several tasks
+		a.settingsManager.CyclePomodoroAttribution()
+		a.saveSettings()
+		return a, nil
+
+	case "s", "S":
+		// Toggle strict mode, which can't be turned off to get out of a break under way
+		if a.settingsManager.Settings.StrictMode && a.timer.BreakLocked() && a.timer.State != model.TimerStopped {
+			a.timer.NoteSkipAttempt()
+			return a, nil
+		}
+		a.settingsManager.SetStrictMode(!a.settingsManager.Settings.StrictMode)
+		a.saveSettings()
+		return a, nil
+
+	case "b", "B":
+		// Toggle the box breathing guide shown during short breaks
+		a.settingsManager.SetBreathingGuide(!a.settingsManager.Settings.BreathingGuide)
+		a.saveSettings()
+		return a, nil
+
+	case "?":
+		// Toggle help text visibility
+		a.showHelpText = !a.showHelpText
+		return a, nil
+	}
+
+	switch msg.String() {
+	case "enter":
+		// Save settings using the saveSettings method
+		a.saveSettings()
+
+		// Return to main view after saving
+		a.view = MainView
+		for _, input := range a.settingsInputs() {
+			input.Blur()
+		}
+		return a, nil
+
+	// Then handle navigation keys
+	case "tab", "down", "j", "J":
+		// Move to next input field
+		a.moveSettingsFocus(1)
+		return a, nil
+
+	case "up", "k", "K", "shift+tab":
+		// Move to previous input field
+		a.moveSettingsFocus(-1)
+		return a, nil
+	}
+
+	// Finally handle text input updates
+	for _, input := range a.settingsInputs() {
+		if input.Focused() {
+			*input, cmd = input.Update(msg)
+			return a, cmd
+		}
+	}
+
+	return a, nil
+}
+
+// settingsInputs returns the settings input fields in navigation order, which is the order settingsView draws them in
+func (a *App) settingsInputs() []*textinput.Model {
+	return []*textinput.Model{
+		&a.pomodoroDurationInput,
+		&a.shortBreakDurationInput,
+		&a.longBreakDurationInput,
+		&a.trashRetentionInput,
+		&a.dailyGoalInput,
+		&a.autoStartGraceInput,
+		&a.flowtimeRatioInput,
+		&a.restDaysInput,
+		&a.profilesInput,
+		&a.breakActivitiesInput,
+		&a.remindersInput,
+		&a.reminderCommandInput,
+	}
+}
+
+// moveSettingsFocus moves the focus by delta through the settings inputs, wrapping around
+func (a *App) moveSettingsFocus(delta int) {
+	inputs := a.settingsInputs()
+
+	// With nothing focused, moving forward starts at the first input
+	current := len(inputs) - 1
+	for i, input := range inputs {
+		if input.Focused() {
+			current = i
+			input.Blur()
+		}
+	}
+
+	next := (current + delta + len(inputs)) % len(inputs)
+	inputs[next].Focus()
+}
+
+// updateSettingsInputs updates the input fields with current settings values
+func (a *App) updateSettingsInputs() {
+	a.pomodoroDurationInput.SetValue(fmt.Sprintf("%d", a.settingsManager.Settings.PomodoroDuration))
+	a.shortBreakDurationInput.SetValue(fmt.Sprintf("%d", a.settingsManager.Settings.ShortBreakDuration))
+	a.longBreakDurationInput.SetValue(fmt.Sprintf("%d", a.settingsManager.Settings.LongBreakDuration))
+	a.trashRetentionInput.SetValue(fmt.Sprintf("%d", a.settingsManager.Settings.TrashRetentionDays))
+	a.dailyGoalInput.SetValue(fmt.Sprintf("%d", a.settingsManager.Settings.DailyGoal))
+	a.flowtimeRatioInput.SetValue(fmt.Sprintf("%d", a.settingsManager.Settings.FlowtimeBreakRatio))
+	a.autoStartGraceInput.SetValue(fmt.Sprintf("%d", a.settingsManager.Settings.AutoStartGrace))
+	a.restDaysInput.SetValue(model.FormatWeekdays(a.settingsManager.Settings.RestDays))
+	a.profilesInput.SetValue(model.FormatProfiles(a.settingsManager.Settings.Profiles))
+	a.breakActivitiesInput.SetValue(strings.Join(a.settingsManager.Settings.BreakActivities, "; "))
+	a.remindersInput.SetValue(model.FormatReminders(a.settingsManager.Settings.Reminders))
+	a.reminderCommandInput.SetValue(a.settingsManager.Settings.ReminderCommand)
+}
+
+// View renders the current UI
+func (a *App) View() string {
 	switch a.view {
 	case MainView:
 		return a.mainView()
@@ -571,6 +1514,18 @@ func (a *App) View() string {
 		return a.addTaskView()
 	case SettingsView:
 		return a.settingsView()
+	case NotesView:
+		return a.notesView()
+	case ArchiveBrowserView:
+		return a.archiveBrowserView()
+	case StatisticsView:
+		return a.statisticsView()
+	case HeatmapCalendarView:
+		return a.heatmapCalendarView()
+	case EstimationView:
+		return a.estimationView()
+	case WorkspaceSwitcherView:
+		return a.workspaceSwitcherView()
 	default:
 		return "Unknown view"
 	}
@@ -612,6 +1567,10 @@ func (a *App) mainView() string {
 		MarginTop(2). // 2 lines of margin at the top
 		Render(a.timerView.Render())
 
+	// Render the daily goal indicator below the timer, if a goal is set
+	a.goalView.SetWidth(a.width - 10)
+	goalSection := a.goalView.Render()
+
 	// Create divider with proper styling
 	divider := lipgloss.NewStyle().
 		Foreground(ColorGrayText).
@@ -626,12 +1585,40 @@ func (a *App) mainView() string {
 		lipgloss.Center,
 		a.taskListView.Render(),
 	)
+
+	// Render the notes and commits of the selected task below the list, if it has any
+	a.taskDetailView.SetWidth(a.width - 40)
+	sections := []string{timerSection}
+	if overlay := a.reminderOverlay(); overlay != "" {
+		sections = append(sections, overlay)
+	}
+	if goalSection != "" {
+		sections = append(sections, goalSection)
+	}
+	// Name the workspace in use, so its task list isn't mistaken for the global one
+	if a.options.Workspace != nil {
+		sections = append(sections, lipgloss.PlaceHorizontal(a.width-10, lipgloss.Center,
+			lipgloss.NewStyle().Foreground(ColorGrayText).Render("workspace: "+a.options.Workspace.Name+"  [W] Switch")))
+	}
+	if profileLine := a.profileLine(); profileLine != "" {
+		sections = append(sections, lipgloss.PlaceHorizontal(a.width-10, lipgloss.Center,
+			lipgloss.NewStyle().Foreground(ColorGrayText).Render(profileLine)))
+	}
+	sections = append(sections, divider)
+	if searchBar := a.renderSearchBar(); searchBar != "" {
+		sections = append(sections, lipgloss.PlaceHorizontal(a.width-10, lipgloss.Center, searchBar))
+	}
+	sections = append(sections, taskListSection)
+	if node, ok := a.taskListView.GetSelectedNode(); ok {
+		if detail := a.taskDetailView.Render(node.Task, a.history.TaskCommits(node.Task.ID)); detail != "" {
+			sections = append(sections, lipgloss.PlaceHorizontal(a.width-10, lipgloss.Center, detail))
+		}
+	}
+
 	// Assemble the inner content
 	innerContent := lipgloss.JoinVertical(
 		lipgloss.Center,
-		timerSection,
-		divider,
-		taskListSection,
+		sections...,
 	)
 
 	// Apply the inner box styling
@@ -655,7 +1642,7 @@ func (a *App) mainView() string {
 	helpTextContent := ""
 	if a.showHelpText {
 		helpTextContent = helpStyle.Render(
-			"\n[S/s] Start/Pause  [r] Reset  [n] New Task  [o] Settings  [h] Toggle Completed  [Space] Toggle Selected  [Enter] Run Task  [Ctrl+C/q] Quit  [?] Hide Help")
+			"\n[S/s] Start/Pause  [r] Reset  [n] New Task  [a] Add Subtask  [c] Collapse  [x] Archive  [d] Trash  [v] Archive/Trash  [t] Stats  [y] Calendar  [i] Estimates  [+/-] Re-estimate  [e] Edit Notes  [g] Link Git Branch  [w] Workspaces  [p] Profile  [/] Search  [1-9] Saved Filter  [o] Settings  [h] Toggle Completed  [Space] Toggle Selected  [Enter] Run Task  [Ctrl+C/q] Quit  [?] Hide Help")
 	}
 
 	return mainContainerStyle.Render(styledContent + helpTextContent + debugModeText)
@@ -732,14 +1719,24 @@ func (a *App) debugView() string {
 func (a *App) addTaskView() string {
 	var builder strings.Builder
 
-	builder.WriteString(TitleStyle.Render("Add New Task"))
+	title := "Add New Task"
+	if parent, found := a.taskManager.GetTask(a.addParentID); found {
+		title = "Add Subtask to: " + parent.Description
+	}
+	builder.WriteString(TitleStyle.Render(title))
 	builder.WriteString("\n\n")
 
 	builder.WriteString("Task Name:\n")
 	builder.WriteString(a.taskInput.View())
 	builder.WriteString("\n\n")
 
-	builder.WriteString("Number of Pomodoros:\n")
+	builder.WriteString("Number of Pomodoros:")
+	// Suggest an estimate from what similar completed tasks actually took
+	if suggestion, ok := a.taskManager.SuggestEstimate(a.taskInput.Value()); ok {
+		builder.WriteString(lipgloss.NewStyle().Foreground(ColorGrayText).Render(
+			fmt.Sprintf("  suggested %d, based on %d similar task(s)", suggestion.Pomodoros, suggestion.Basis)))
+	}
+	builder.WriteString("\n")
 	builder.WriteString(a.pomodorosInput.View())
 	builder.WriteString("\n\n")
 
@@ -753,6 +1750,139 @@ func (a *App) addTaskView() string {
 	return BoxStyle.Render(builder.String())
 }
 
+// notesView renders the notes editor view
+func (a *App) notesView() string {
+	var builder strings.Builder
+
+	title := "Notes"
+	if task, found := a.taskManager.GetTask(a.notesTaskID); found {
+		title = "Notes: " + task.Description
+	}
+	builder.WriteString(TitleStyle.Render(title))
+	builder.WriteString("\n\n")
+
+	builder.WriteString(a.notesInput.View())
+	builder.WriteString("\n\n")
+
+	// Instructions with help toggle
+	if a.showHelpText {
+		builder.WriteString("Press Ctrl+S to save, Esc to cancel. Markdown is rendered in the task detail pane")
+	} else {
+		builder.WriteString("Press Ctrl+S to save, Esc to cancel")
+	}
+
+	return BoxStyle.Render(builder.String())
+}
+
+// archiveBrowserView renders the archive and trash browser
+func (a *App) archiveBrowserView() string {
+	var builder strings.Builder
+
+	builder.WriteString(TitleStyle.Render("Archive & Trash"))
+	builder.WriteString("\n\n")
+
+	a.archiveView.SetWidth(a.width - 16)
+	builder.WriteString(a.archiveView.Render())
+	builder.WriteString("\n\n")
+
+	// Instructions with help toggle
+	if a.showHelpText {
+		if a.archiveView.ShowingTrash() {
+			builder.WriteString("[Tab] Archive  [R] Restore  [D] Delete forever  [E] Empty trash  [Esc] Back  [?] Hide help")
+		} else {
+			builder.WriteString("[Tab] Trash  [R] Restore  [D] Move to trash  [Esc] Back  [?] Hide help")
+		}
+	} else {
+		builder.WriteString("Press ? to show help")
+	}
+
+	return BoxStyle.Render(builder.String())
+}
+
+// workspaceSwitcherView renders the workspace switcher
+func (a *App) workspaceSwitcherView() string {
+	var builder strings.Builder
+
+	builder.WriteString(TitleStyle.Render("Workspaces"))
+	builder.WriteString("\n\n")
+
+	a.workspaceView.SetWidth(a.width - 16)
+	builder.WriteString(a.workspaceView.Render())
+	builder.WriteString("\n\n")
+
+	// Instructions with help toggle
+	if a.showHelpText {
+		builder.WriteString("[j/k] Move  [Enter] Switch  [Esc] Back  [?] Hide help")
+	} else {
+		builder.WriteString("Press ? to show help")
+	}
+
+	return BoxStyle.Render(builder.String())
+}
+
+// statisticsView renders the productivity statistics view
+func (a *App) statisticsView() string {
+	var builder strings.Builder
+
+	builder.WriteString(TitleStyle.Render("Statistics"))
+	builder.WriteString("\n\n")
+
+	a.statsView.SetWidth(a.width - 16)
+	builder.WriteString(a.statsView.Render())
+	builder.WriteString("\n\n")
+
+	// Instructions with help toggle
+	if a.showHelpText {
+		builder.WriteString("[+/-] More/fewer weeks  [Esc] Back  [?] Hide help")
+	} else {
+		builder.WriteString("Press ? to show help")
+	}
+
+	return BoxStyle.Render(builder.String())
+}
+
+// estimationView renders the estimation accuracy view
+func (a *App) estimationView() string {
+	var builder strings.Builder
+
+	builder.WriteString(TitleStyle.Render("Estimation Accuracy"))
+	builder.WriteString("\n\n")
+
+	a.estimatesView.SetWidth(a.width - 16)
+	builder.WriteString(a.estimatesView.Render())
+	builder.WriteString("\n\n")
+
+	// Instructions with help toggle
+	if a.showHelpText {
+		builder.WriteString("[+/-] More/fewer weeks  [Esc] Back  [?] Hide help")
+	} else {
+		builder.WriteString("Press ? to show help")
+	}
+
+	return BoxStyle.Render(builder.String())
+}
+
+// heatmapCalendarView renders the calendar heatmap view
+func (a *App) heatmapCalendarView() string {
+	var builder strings.Builder
+
+	builder.WriteString(TitleStyle.Render("Focus Calendar"))
+	builder.WriteString("\n\n")
+
+	a.heatmapView.SetWidth(a.width - 16)
+	builder.WriteString(a.heatmapView.Render())
+	builder.WriteString("\n\n")
+
+	// Instructions with help toggle
+	if a.showHelpText {
+		builder.WriteString("[←/→] Week  [↑/↓] Day  [ [ / ] ] Month  [Esc] Back  [?] Hide help")
+	} else {
+		builder.WriteString("Press ? to show help")
+	}
+
+	return BoxStyle.Render(builder.String())
+}
+
 // settingsView renders the settings view
 func (a *App) settingsView() string {
 	var builder strings.Builder
@@ -763,7 +1893,16 @@ func (a *App) settingsView() string {
 	// Initialize input values when opening the settings view
 	if !a.pomodoroDurationInput.Focused() &&
 		!a.shortBreakDurationInput.Focused() &&
-		!a.longBreakDurationInput.Focused() {
+		!a.longBreakDurationInput.Focused() &&
+		!a.trashRetentionInput.Focused() &&
+		!a.dailyGoalInput.Focused() &&
+		!a.flowtimeRatioInput.Focused() &&
+		!a.autoStartGraceInput.Focused() &&
+		!a.restDaysInput.Focused() &&
+		!a.profilesInput.Focused() &&
+		!a.breakActivitiesInput.Focused() &&
+		!a.remindersInput.Focused() &&
+		!a.reminderCommandInput.Focused() {
 		a.updateSettingsInputs()
 		a.pomodoroDurationInput.Focus()
 	}
@@ -786,6 +1925,60 @@ func (a *App) settingsView() string {
 	builder.WriteString(a.longBreakDurationInput.View())
 	builder.WriteString("\n\n")
 
+	// Trash retention
+	builder.WriteString(lipgloss.NewStyle().Bold(true).Render("Purge Trash After (days, 0 = never):"))
+	builder.WriteString("\n")
+	builder.WriteString(a.trashRetentionInput.View())
+	builder.WriteString("\n\n")
+
+	// Daily goal
+	builder.WriteString(lipgloss.NewStyle().Bold(true).Render("Daily Goal (pomodoros, 0 = none):"))
+	builder.WriteString("\n")
+	builder.WriteString(a.dailyGoalInput.View())
+	builder.WriteString("\n\n")
+
+	// Auto-start countdown
+	builder.WriteString(lipgloss.NewStyle().Bold(true).Render("Auto-start Countdown (seconds):"))
+	builder.WriteString("\n")
+	builder.WriteString(a.autoStartGraceInput.View())
+	builder.WriteString("\n\n")
+
+	// Flowtime break ratio
+	builder.WriteString(lipgloss.NewStyle().Bold(true).Render("Flowtime Break (1 minute per N minutes of focus):"))
+	builder.WriteString("\n")
+	builder.WriteString(a.flowtimeRatioInput.View())
+	builder.WriteString("\n\n")
+
+	// Rest days
+	builder.WriteString(lipgloss.NewStyle().Bold(true).Render("Rest Days (don't break the streak):"))
+	builder.WriteString("\n")
+	builder.WriteString(a.restDaysInput.View())
+	builder.WriteString("\n\n")
+
+	// Profiles
+	builder.WriteString(lipgloss.NewStyle().Bold(true).Render("Profiles (name focus/short/long, separated by ;):"))
+	builder.WriteString("\n")
+	builder.WriteString(a.profilesInput.View())
+	builder.WriteString("\n\n")
+
+	// Break activities
+	builder.WriteString(lipgloss.NewStyle().Bold(true).Render("Break Activities (separated by ;):"))
+	builder.WriteString("\n")
+	builder.WriteString(a.breakActivitiesInput.View())
+	builder.WriteString("\n\n")
+
+	// Reminders
+	builder.WriteString(lipgloss.NewStyle().Bold(true).Render("Focus Reminders (every N minutes, separated by ;):"))
+	builder.WriteString("\n")
+	builder.WriteString(a.remindersInput.View())
+	builder.WriteString("\n\n")
+
+	// Reminder notification command
+	builder.WriteString(lipgloss.NewStyle().Bold(true).Render("Reminder Command (receives the message):"))
+	builder.WriteString("\n")
+	builder.WriteString(a.reminderCommandInput.View())
+	builder.WriteString("\n\n")
+
 	// Auto-start Breaks Option
 	autoStartStatus := "OFF"
 	autoStartColor := lipgloss.Color("#BB566B") // Red-ish for OFF
@@ -801,6 +1994,79 @@ func (a *App) settingsView() string {
 	builder.WriteString(lipgloss.NewStyle().Foreground(ColorGrayText).Render("[A] to toggle"))
 	builder.WriteString("\n\n")
 
+	// Auto-start Pomodoros Option
+	autoStartPomodorosStatus := "OFF"
+	autoStartPomodorosColor := lipgloss.Color("#BB566B")
+	if a.settingsManager.Settings.AutoStartPomodoros {
+		autoStartPomodorosStatus = "ON"
+		autoStartPomodorosColor = lipgloss.Color("#7BC0AB")
+	}
+
+	builder.WriteString(lipgloss.NewStyle().Bold(true).Render("Auto-start pomodoros:"))
+	builder.WriteString(" ")
+	builder.WriteString(lipgloss.NewStyle().Foreground(autoStartPomodorosColor).Bold(true).Render(autoStartPomodorosStatus))
+	builder.WriteString(" ")
+	builder.WriteString(lipgloss.NewStyle().Foreground(ColorGrayText).Render("[P] to toggle"))
+	builder.WriteString("\n\n")
+
+	// Flowtime Option
+	flowtimeStatus := "OFF"
+	flowtimeColor := lipgloss.Color("#BB566B")
+	if a.settingsManager.Settings.Flowtime {
+		flowtimeStatus = "ON"
+		flowtimeColor = lipgloss.Color("#7BC0AB")
+	}
+
+	builder.WriteString(lipgloss.NewStyle().Bold(true).Render("Flowtime (count focus up, break when ready):"))
+	builder.WriteString(" ")
+	builder.WriteString(lipgloss.NewStyle().Foreground(flowtimeColor).Bold(true).Render(flowtimeStatus))
+	builder.WriteString(" ")
+	builder.WriteString(lipgloss.NewStyle().Foreground(ColorGrayText).Render("[F] to toggle"))
+	builder.WriteString("\n\n")
+
+	// Pomodoro attribution Option
+	attributions := map[string]string{
+		model.AttributeSplit:    "split by time",
+		model.AttributeMajority: "task with the most time",
+		model.AttributeLast:     "last task",
+	}
+	builder.WriteString(lipgloss.NewStyle().Bold(true).Render("Pomodoros spent on several tasks go to:"))
+	builder.WriteString(" ")
+	builder.WriteString(lipgloss.NewStyle().Foreground(ColorTasksHeader).Bold(true).Render(attributions[a.settingsManager.Settings.GetPomodoroAttribution()]))
+	builder.WriteString(" ")
+	builder.WriteString(lipgloss.NewStyle().Foreground(ColorGrayText).Render("[T] to change"))
+	builder.WriteString("\n\n")
+
+	// Strict mode Option
+	strictStatus := "OFF"
+	strictColor := lipgloss.Color("#BB566B")
+	if a.settingsManager.Settings.StrictMode {
+		strictStatus = "ON"
+		strictColor = lipgloss.Color("#7BC0AB")
+	}
+
+	builder.WriteString(lipgloss.NewStyle().Bold(true).Render("Strict mode (breaks can't be skipped):"))
+	builder.WriteString(" ")
+	builder.WriteString(lipgloss.NewStyle().Foreground(strictColor).Bold(true).Render(strictStatus))
+	builder.WriteString(" ")
+	builder.WriteString(lipgloss.NewStyle().Foreground(ColorGrayText).Render("[S] to toggle"))
+	builder.WriteString("\n\n")
+
+	// Breathing guide Option
+	breathingStatus := "OFF"
+	breathingColor := lipgloss.Color("#BB566B")
+	if a.settingsManager.Settings.BreathingGuide {
+		breathingStatus = "ON"
+		breathingColor = lipgloss.Color("#7BC0AB")
+	}
+
+	builder.WriteString(lipgloss.NewStyle().Bold(true).Render("Box breathing guide in short breaks:"))
+	builder.WriteString(" ")
+	builder.WriteString(lipgloss.NewStyle().Foreground(breathingColor).Bold(true).Render(breathingStatus))
+	builder.WriteString(" ")
+	builder.WriteString(lipgloss.NewStyle().Foreground(ColorGrayText).Render("[B] to toggle"))
+	builder.WriteString("\n\n")
+
 	// Instructions with help toggle
 	if a.showHelpText {
 		builder.WriteString("Press Enter to save, Esc to cancel, Tab/↑/↓ to navigate, ? to hide help")
@@ -889,6 +2155,12 @@ func (a *App) RenderTaskListView() string {
 	return a.debugView()
 }
 
+// StatusLine returns a one-line summary of today's progress and the streak
+// Used by the --status flag, e.g. for shell prompts and status bars
+func (a *App) StatusLine() string {
+	return model.ComputeStreak(a.history, a.settingsManager.Settings, time.Now()).Summary()
+}
+
 // saveSettings saves the current settings via the storage manager
 func (a *App) saveSettings() {
 	// Apply current input values to settings
@@ -916,6 +2188,59 @@ func (a *App) saveSettings() {
 		}
 	}
 
+	if a.trashRetentionInput.Value() != "" {
+		days := -1
+		fmt.Sscanf(a.trashRetentionInput.Value(), "%d", &days)
+		if days >= 0 {
+			a.settingsManager.SetTrashRetentionDays(days)
+		}
+	}
+
+	if a.dailyGoalInput.Value() != "" {
+		goal := -1
+		fmt.Sscanf(a.dailyGoalInput.Value(), "%d", &goal)
+		if goal >= 0 {
+			a.settingsManager.SetDailyGoal(goal)
+		}
+	}
+
+	if a.flowtimeRatioInput.Value() != "" {
+		var ratio int
+		fmt.Sscanf(a.flowtimeRatioInput.Value(), "%d", &ratio)
+		if ratio > 0 {
+			a.settingsManager.SetFlowtimeBreakRatio(ratio)
+		}
+	}
+
+	if a.autoStartGraceInput.Value() != "" {
+		seconds := -1
+		fmt.Sscanf(a.autoStartGraceInput.Value(), "%d", &seconds)
+		if seconds >= 0 {
+			a.settingsManager.SetAutoStartGrace(seconds)
+		}
+	}
+
+	// Rest days may be cleared entirely; invalid lists keep the previous days
+	if days, err := model.ParseWeekdays(a.restDaysInput.Value()); err == nil {
+		a.settingsManager.SetRestDays(days)
+	}
+
+	// Likewise for profiles, which may all be removed
+	if profiles, err := model.ParseProfiles(a.profilesInput.Value()); err == nil {
+		a.settingsManager.SetProfiles(profiles)
+	}
+	activities := make([]string, 0)
+	for _, activity := range strings.Split(a.breakActivitiesInput.Value(), ";") {
+		if activity = strings.TrimSpace(activity); activity != "" {
+			activities = append(activities, activity)
+		}
+	}
+	a.settingsManager.SetBreakActivities(activities)
+	if reminders, err := model.ParseReminders(a.remindersInput.Value()); err == nil {
+		a.settingsManager.SetReminders(reminders)
+	}
+	a.settingsManager.SetReminderCommand(a.reminderCommandInput.Value())
+
 	// Save to storage
 	if a.storageManager != nil {
 		_ = a.storageManager.SaveSettings()
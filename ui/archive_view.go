@@ -0,0 +1,177 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jackrudenko/pomodorocli/model"
+)
+
+// ArchiveView represents the browser for archived and trashed tasks
+type ArchiveView struct {
+	taskManager   *model.TaskManager
+	settings      *model.Settings
+	width         int
+	showTrash     bool
+	selectedIndex int
+}
+
+// NewArchiveView creates a new archive view
+func NewArchiveView(taskManager *model.TaskManager, settings *model.Settings, width int) *ArchiveView {
+	return &ArchiveView{
+		taskManager:   taskManager,
+		settings:      settings,
+		width:         width,
+		showTrash:     false,
+		selectedIndex: 0,
+	}
+}
+
+// SetWidth updates the width of the archive view
+func (v *ArchiveView) SetWidth(width int) {
+	v.width = width
+}
+
+// ToggleTab switches between the archive and the trash
+func (v *ArchiveView) ToggleTab() {
+	v.showTrash = !v.showTrash
+	v.selectedIndex = 0
+}
+
+// ShowingTrash reports whether the trash tab is shown
+func (v *ArchiveView) ShowingTrash() bool {
+	return v.showTrash
+}
+
+// tasks returns the tasks of the current tab
+func (v *ArchiveView) tasks() []model.Task {
+	if v.showTrash {
+		return v.taskManager.TrashedTasks()
+	}
+	return v.taskManager.ArchivedTasks()
+}
+
+// MoveSelectionDown moves the selection down
+func (v *ArchiveView) MoveSelectionDown() {
+	if v.selectedIndex < len(v.tasks())-1 {
+		v.selectedIndex++
+	}
+}
+
+// MoveSelectionUp moves the selection up
+func (v *ArchiveView) MoveSelectionUp() {
+	if v.selectedIndex > 0 {
+		v.selectedIndex--
+	}
+}
+
+// GetSelectedTask returns the selected task, or false if the tab is empty
+func (v *ArchiveView) GetSelectedTask() (model.Task, bool) {
+	tasks := v.tasks()
+	if len(tasks) == 0 {
+		return model.Task{}, false
+	}
+	if v.selectedIndex >= len(tasks) {
+		v.selectedIndex = len(tasks) - 1
+	}
+	return tasks[v.selectedIndex], true
+}
+
+// RestoreSelectedTask brings the selected task back to the task list
+func (v *ArchiveView) RestoreSelectedTask() bool {
+	task, ok := v.GetSelectedTask()
+	if !ok {
+		return false
+	}
+	return v.taskManager.RestoreTask(task.ID)
+}
+
+// DeleteSelectedTask moves an archived task to the trash, or permanently deletes a trashed one
+func (v *ArchiveView) DeleteSelectedTask() bool {
+	task, ok := v.GetSelectedTask()
+	if !ok {
+		return false
+	}
+	if v.showTrash {
+		return v.taskManager.DeleteTask(task.ID)
+	}
+	return v.taskManager.TrashTask(task.ID, time.Now())
+}
+
+// EmptyTrash permanently deletes everything in the trash
+func (v *ArchiveView) EmptyTrash() int {
+	v.selectedIndex = 0
+	return v.taskManager.EmptyTrash()
+}
+
+// Render renders the archive view
+func (v *ArchiveView) Render() string {
+	tasks := v.tasks()
+
+	// Tabs, highlighting the current one
+	activeTab := TasksHeaderStyle.Bold(true).Underline(true)
+	inactiveTab := lipgloss.NewStyle().Foreground(ColorGrayText)
+	archiveTab, trashTab := activeTab, inactiveTab
+	if v.showTrash {
+		archiveTab, trashTab = inactiveTab, activeTab
+	}
+	tabs := lipgloss.JoinHorizontal(
+		lipgloss.Left,
+		archiveTab.Render(fmt.Sprintf("Archive (%d)", len(v.taskManager.ArchivedTasks()))),
+		"    ",
+		trashTab.Render(fmt.Sprintf("Trash (%d)", len(v.taskManager.TrashedTasks()))),
+	)
+
+	lines := []string{tabs, ""}
+	for i, task := range tasks {
+		prefix := "   "
+		descStyle := TaskStyle
+		if i == v.selectedIndex {
+			prefix = lipgloss.NewStyle().Foreground(ColorTaskTag).Bold(true).Render("👉 ")
+			descStyle = descStyle.Bold(true)
+		}
+
+		line := fmt.Sprintf("%s%s  %s  %s",
+			prefix,
+			TaskProgressStyle.Render(task.PomodoroProgress()),
+			TaskTimeStyle.Render(task.FormattedTimeSpent()),
+			descStyle.Render(task.Description))
+		if info := v.trashInfo(task); info != "" {
+			line += "  " + lipgloss.NewStyle().Foreground(ColorGrayText).Render(info)
+		}
+		lines = append(lines, line)
+	}
+
+	if len(tasks) == 0 {
+		if v.showTrash {
+			lines = append(lines, TaskStyle.Render("The trash is empty."))
+		} else {
+			lines = append(lines, TaskStyle.Render("No archived tasks. Archive completed tasks with [X] in the main view."))
+		}
+	}
+
+	return lipgloss.NewStyle().
+		Padding(0, 2).
+		Width(v.width).
+		Render(strings.Join(lines, "\n"))
+}
+
+// trashInfo describes when a trashed task was deleted and when it will be purged
+func (v *ArchiveView) trashInfo(task model.Task) string {
+	if !task.InTrash() {
+		return ""
+	}
+
+	age := time.Since(*task.TrashedAt)
+	info := fmt.Sprintf("trashed %dd ago", int(age.Hours()/24))
+	if v.settings != nil && v.settings.TrashRetentionDays > 0 {
+		remaining := v.settings.GetTrashRetention() - age
+		if remaining < 0 {
+			remaining = 0
+		}
+		info += fmt.Sprintf(", purged in %dd", int(remaining.Hours()/24))
+	}
+	return info
+}
@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// boxBreathingSide is the number of seconds of each side of the box: breathe in, hold,
+// breathe out and hold again
+const boxBreathingSide = 4
+
+// boxBreathingPhases names the phase of each side of the box, clockwise from the top
+var boxBreathingPhases = []string{"Breathe in", "Hold", "Breathe out", "Hold"}
+
+// renderBoxBreathing renders the box breathing guide after the given time into the break:
+// a dot travels clockwise around a square, one side per phase
+func renderBoxBreathing(elapsed time.Duration) string {
+	second := int(elapsed/time.Second) % (4 * boxBreathingSide)
+	phase := second / boxBreathingSide
+	step := second % boxBreathingSide
+
+	// Position of the dot on the square's corners and edges
+	var row, col int
+	switch phase {
+	case 0:
+		row, col = 0, step
+	case 1:
+		row, col = step, boxBreathingSide
+	case 2:
+		row, col = boxBreathingSide, boxBreathingSide-step
+	default:
+		row, col = boxBreathingSide-step, 0
+	}
+
+	dotStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#7BC0AB")).Bold(true)
+	edgeStyle := lipgloss.NewStyle().Foreground(ColorGrayText)
+
+	lines := make([]string, 0, boxBreathingSide+2)
+	for r := 0; r <= boxBreathingSide; r++ {
+		cells := make([]string, 0, boxBreathingSide+1)
+		for c := 0; c <= boxBreathingSide; c++ {
+			switch {
+			case r == row && c == col:
+				cells = append(cells, dotStyle.Render("●"))
+			case r == 0 || r == boxBreathingSide || c == 0 || c == boxBreathingSide:
+				cells = append(cells, edgeStyle.Render("·"))
+			default:
+				cells = append(cells, " ")
+			}
+		}
+		lines = append(lines, strings.Join(cells, "  "))
+	}
+	lines = append(lines, dotStyle.Render(fmt.Sprintf("%s %d", boxBreathingPhases[phase], boxBreathingSide-step)))
+
+	return lipgloss.NewStyle().
+		Align(lipgloss.Center).
+		PaddingBottom(1).
+		Render(lipgloss.JoinVertical(lipgloss.Center, lines...))
+}
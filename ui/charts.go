@@ -0,0 +1,108 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// blockLevels are the partial block characters used to draw fractional bar heights
+var blockLevels = []string{" ", "▁", "▂", "▃", "▄", "▅", "▆", "▇", "█"}
+
+// renderColumnChart draws values as vertical bars of the given height in rows.
+// Each column is colWidth characters wide; labels (may be empty) are printed below.
+func renderColumnChart(values []float64, labels []string, height, colWidth int, color lipgloss.Color) string {
+	if height < 1 {
+		height = 1
+	}
+	if colWidth < 1 {
+		colWidth = 1
+	}
+
+	maxValue := 0.0
+	for _, value := range values {
+		maxValue = math.Max(maxValue, value)
+	}
+
+	barStyle := lipgloss.NewStyle().Foreground(color)
+	axisStyle := lipgloss.NewStyle().Foreground(ColorGrayText)
+
+	// Scale label on the left shows the maximum value
+	scale := fmt.Sprintf("%.0f", maxValue)
+	gutter := len(scale) + 1
+
+	rows := make([]string, 0, height+2)
+	for row := height - 1; row >= 0; row-- {
+		var line strings.Builder
+		if row == height-1 {
+			line.WriteString(axisStyle.Render(fmt.Sprintf("%*s ", len(scale), scale)))
+		} else {
+			line.WriteString(strings.Repeat(" ", gutter))
+		}
+
+		for _, value := range values {
+			// Height of the bar in eighths of a row
+			eighths := 0
+			if maxValue > 0 {
+				eighths = int(math.Round(value / maxValue * float64(height*8)))
+			}
+			if value > 0 && eighths == 0 {
+				eighths = 1 // Keep non-zero days visible
+			}
+
+			level := eighths - row*8
+			if level < 0 {
+				level = 0
+			} else if level > 8 {
+				level = 8
+			}
+			line.WriteString(barStyle.Render(strings.Repeat(blockLevels[level], colWidth)))
+		}
+		rows = append(rows, line.String())
+	}
+
+	// Baseline and labels
+	rows = append(rows, axisStyle.Render(strings.Repeat(" ", gutter)+strings.Repeat("─", len(values)*colWidth)))
+	var labelLine strings.Builder
+	labelLine.WriteString(strings.Repeat(" ", gutter))
+	for i := 0; i < len(labels) && i < len(values); {
+		label := labels[i]
+		if label == "" {
+			labelLine.WriteString(strings.Repeat(" ", colWidth))
+			i++
+			continue
+		}
+		// Let a label spill over into the following empty columns
+		labelLine.WriteString(label)
+		used := (len(label) + colWidth - 1) / colWidth
+		labelLine.WriteString(strings.Repeat(" ", used*colWidth-len(label)))
+		i += used
+	}
+	rows = append(rows, axisStyle.Render(labelLine.String()))
+
+	return strings.Join(rows, "\n")
+}
+
+// renderBarRow draws a single labelled horizontal bar scaled against maxValue
+func renderBarRow(label string, labelWidth int, value, maxValue float64, barWidth int, color lipgloss.Color, suffix string) string {
+	filled := 0
+	if maxValue > 0 {
+		filled = int(math.Round(value / maxValue * float64(barWidth)))
+	}
+	if value > 0 && filled == 0 {
+		filled = 1
+	}
+
+	if len([]rune(label)) > labelWidth {
+		label = string([]rune(label)[:labelWidth-1]) + "…"
+	}
+
+	return fmt.Sprintf("%-*s %s%s %s",
+		labelWidth,
+		label,
+		lipgloss.NewStyle().Foreground(color).Render(strings.Repeat("█", filled)),
+		lipgloss.NewStyle().Foreground(ColorBorder).Render(strings.Repeat("░", barWidth-filled)),
+		lipgloss.NewStyle().Foreground(ColorGrayText).Render(suffix))
+}
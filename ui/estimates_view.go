@@ -0,0 +1,149 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jackrudenko/pomodorocli/model"
+)
+
+const (
+	// Default and maximum number of weeks shown in the estimate trend
+	defaultEstimateWeeks = 8
+	maxEstimateWeeks     = 26
+	// Number of projects listed in the estimate breakdown
+	maxEstimateProjects = 8
+)
+
+// EstimatesView represents the estimation accuracy report
+type EstimatesView struct {
+	taskManager *model.TaskManager
+	width       int
+	weeks       int
+}
+
+// NewEstimatesView creates a new estimation accuracy view
+func NewEstimatesView(taskManager *model.TaskManager, width int) *EstimatesView {
+	return &EstimatesView{
+		taskManager: taskManager,
+		width:       width,
+		weeks:       defaultEstimateWeeks,
+	}
+}
+
+// SetWidth updates the width of the estimates view
+func (v *EstimatesView) SetWidth(width int) {
+	v.width = width
+}
+
+// MoreWeeks extends the trend by one week
+func (v *EstimatesView) MoreWeeks() {
+	if v.weeks < maxEstimateWeeks {
+		v.weeks++
+	}
+}
+
+// FewerWeeks shortens the trend by one week
+func (v *EstimatesView) FewerWeeks() {
+	if v.weeks > 1 {
+		v.weeks--
+	}
+}
+
+// Render renders the estimates view
+func (v *EstimatesView) Render() string {
+	report := v.taskManager.EstimationReport(v.weeks, time.Now())
+
+	sectionStyle := TasksHeaderStyle.Bold(true)
+	sections := []string{
+		v.renderSummary(report.Overall),
+		"",
+		sectionStyle.Render("By project"),
+		v.renderProjects(report.ByProject),
+		"",
+		sectionStyle.Render(fmt.Sprintf("Estimate error per week (last %d)", v.weeks)),
+		v.renderTrend(report.ByWeek),
+	}
+
+	return lipgloss.NewStyle().
+		Padding(0, 2).
+		Width(v.width).
+		Render(strings.Join(sections, "\n"))
+}
+
+// renderSummary renders the headline accuracy numbers across all completed tasks
+func (v *EstimatesView) renderSummary(overall model.EstimateAccuracy) string {
+	labelStyle := lipgloss.NewStyle().Foreground(ColorGrayText)
+	valueStyle := lipgloss.NewStyle().Foreground(ColorText).Bold(true)
+
+	if overall.Tasks == 0 {
+		return labelStyle.Render("No completed tasks yet. Accuracy is measured once tasks are marked done.")
+	}
+
+	items := []string{
+		labelStyle.Render("Tasks ") + valueStyle.Render(fmt.Sprintf("%d", overall.Tasks)),
+		labelStyle.Render("Estimated ") + valueStyle.Render(fmt.Sprintf("%d 🍅", overall.Estimated)),
+		labelStyle.Render("Actual ") + valueStyle.Render(fmt.Sprintf("%s 🍅", model.FormatPomodoros(overall.Actual))),
+		labelStyle.Render("Bias ") + valueStyle.Render(formatBias(overall.Bias())),
+		labelStyle.Render("Avg error ") + valueStyle.Render(fmt.Sprintf("±%.1f 🍅", overall.MeanAbsoluteError())),
+	}
+	counts := fmt.Sprintf("%d overran, %d finished early, %d re-estimated", overall.Overruns, overall.Underruns, overall.Reestimated)
+
+	return strings.Join(items, "   ") + "\n" + labelStyle.Render(counts)
+}
+
+// renderProjects renders a row per project with its bias and average error
+func (v *EstimatesView) renderProjects(projects []model.EstimateAccuracy) string {
+	if len(projects) == 0 {
+		return lipgloss.NewStyle().Foreground(ColorGrayText).Render("No completed tasks yet")
+	}
+
+	rows := make([]string, 0, len(projects))
+	for i, project := range projects {
+		if i == maxEstimateProjects {
+			rows = append(rows, lipgloss.NewStyle().Foreground(ColorGrayText).Render(
+				fmt.Sprintf("… and %d more", len(projects)-maxEstimateProjects)))
+			break
+		}
+		rows = append(rows, v.renderBiasRow(project.Name, 16, project,
+			fmt.Sprintf("%d task(s)  %d→%s 🍅  ±%.1f", project.Tasks, project.Estimated, model.FormatPomodoros(project.Actual), project.MeanAbsoluteError())))
+	}
+	return strings.Join(rows, "\n")
+}
+
+// renderTrend renders a row per week so calibration over time is visible
+func (v *EstimatesView) renderTrend(weeks []model.EstimateAccuracy) string {
+	rows := make([]string, 0, len(weeks))
+	for _, week := range weeks {
+		if week.Tasks == 0 {
+			rows = append(rows, fmt.Sprintf("%-7s %s", week.Start.Format("Jan 02"),
+				lipgloss.NewStyle().Foreground(ColorGrayText).Render("–")))
+			continue
+		}
+		rows = append(rows, v.renderBiasRow(week.Start.Format("Jan 02"), 7, week,
+			fmt.Sprintf("%d task(s)  ±%.1f", week.Tasks, week.MeanAbsoluteError())))
+	}
+	return strings.Join(rows, "\n")
+}
+
+// renderBiasRow draws a bar whose length is the size of the bias, red for overruns
+// and green for overestimates, capped at 100%
+func (v *EstimatesView) renderBiasRow(label string, labelWidth int, accuracy model.EstimateAccuracy, suffix string) string {
+	bias := accuracy.Bias()
+	color := ColorTasksHeader
+	if bias > 0 {
+		color = ColorStopButton
+	}
+
+	barWidth := clamp(v.width-60, 6, 30)
+	return renderBarRow(label, labelWidth, math.Min(math.Abs(bias), 1), 1, barWidth, color,
+		fmt.Sprintf("%-6s %s", formatBias(bias), suffix))
+}
+
+// formatBias formats a relative estimate error as a signed percentage
+func formatBias(bias float64) string {
+	return fmt.Sprintf("%+.0f%%", bias*100)
+}
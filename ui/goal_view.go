@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jackrudenko/pomodorocli/model"
+)
+
+// Maximum number of cells in the daily goal progress bar
+const maxGoalCells = 12
+
+// GoalView represents the daily goal progress and streak indicator
+type GoalView struct {
+	history  *model.History
+	settings *model.Settings
+	width    int
+}
+
+// NewGoalView creates a new daily goal indicator
+func NewGoalView(history *model.History, settings *model.Settings, width int) *GoalView {
+	return &GoalView{
+		history:  history,
+		settings: settings,
+		width:    width,
+	}
+}
+
+// SetWidth updates the width of the goal view
+func (v *GoalView) SetWidth(width int) {
+	v.width = width
+}
+
+// Render renders today's progress towards the goal and the current streak
+func (v *GoalView) Render() string {
+	streak := model.ComputeStreak(v.history, *v.settings, time.Now())
+	if streak.Goal <= 0 {
+		return ""
+	}
+
+	labelStyle := lipgloss.NewStyle().Foreground(ColorGrayText)
+	color := ColorTasksHeader
+	if streak.GoalMetToday() {
+		color = ColorStopButton
+	}
+
+	// Scale the bar down for large goals
+	cells := streak.Goal
+	if cells > maxGoalCells {
+		cells = maxGoalCells
+	}
+	filled := streak.Today * cells / streak.Goal
+	if filled > cells {
+		filled = cells
+	}
+	bar := lipgloss.NewStyle().Foreground(color).Render(strings.Repeat("▰", filled)) +
+		labelStyle.Render(strings.Repeat("▱", cells-filled))
+
+	line := labelStyle.Render("Today ") +
+		lipgloss.NewStyle().Foreground(color).Bold(true).Render(fmt.Sprintf("%d/%d", streak.Today, streak.Goal)) +
+		" " + bar
+
+	if streak.Current > 0 {
+		line += labelStyle.Render(fmt.Sprintf("   🔥 %d-day streak", streak.Current))
+	}
+
+	return lipgloss.PlaceHorizontal(v.width, lipgloss.Center, line)
+}
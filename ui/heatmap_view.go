@@ -0,0 +1,284 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jackrudenko/pomodorocli/model"
+	colorful "github.com/lucasb-eyer/go-colorful"
+)
+
+const (
+	// Number of weeks shown in the heatmap
+	heatmapWeeks = 53
+	// Number of intensity levels above "no focus"
+	heatmapLevels = 4
+	// Maximum number of sessions listed for the selected day
+	maxHeatmapSessions = 8
+)
+
+// HeatmapView represents the year-at-a-glance calendar of focus time
+type HeatmapView struct {
+	history *model.History
+	width   int
+	// The selected day
+	cursor time.Time
+	// The last day of the displayed window
+	windowEnd time.Time
+	// Cell colours from no focus to the most focused days
+	palette []lipgloss.Color
+}
+
+// NewHeatmapView creates a new heatmap view with the cursor on today
+func NewHeatmapView(history *model.History, width int) *HeatmapView {
+	today := model.StartOfDay(time.Now())
+	return &HeatmapView{
+		history:   history,
+		width:     width,
+		cursor:    today,
+		windowEnd: today,
+		palette:   heatmapPalette(),
+	}
+}
+
+// heatmapPalette blends from the border colour to the accent colour in perceptually even steps
+func heatmapPalette() []lipgloss.Color {
+	palette := []lipgloss.Color{ColorBorder}
+
+	start, errStart := colorful.Hex("#2A3B36")
+	end, errEnd := colorful.Hex(string(ColorTasksHeader))
+	if errStart != nil || errEnd != nil {
+		// Fall back to the accent colour for every level
+		for i := 0; i < heatmapLevels; i++ {
+			palette = append(palette, ColorTasksHeader)
+		}
+		return palette
+	}
+
+	for i := 0; i < heatmapLevels; i++ {
+		t := float64(i) / float64(heatmapLevels-1)
+		palette = append(palette, lipgloss.Color(start.BlendLab(end, t).Clamped().Hex()))
+	}
+	return palette
+}
+
+// SetWidth updates the width of the heatmap view
+func (v *HeatmapView) SetWidth(width int) {
+	v.width = width
+}
+
+// MoveCursor moves the selected day by the given number of days, never past today
+func (v *HeatmapView) MoveCursor(days int) {
+	v.setCursor(v.cursor.AddDate(0, 0, days))
+}
+
+// MoveCursorMonths moves the selected day by the given number of months, never past today
+func (v *HeatmapView) MoveCursorMonths(months int) {
+	v.setCursor(v.cursor.AddDate(0, months, 0))
+}
+
+// setCursor selects a day and scrolls the window so it stays visible
+func (v *HeatmapView) setCursor(day time.Time) {
+	today := model.StartOfDay(time.Now())
+	day = model.StartOfDay(day)
+	if day.After(today) {
+		day = today
+	}
+	v.cursor = day
+
+	if day.After(v.windowEnd) {
+		v.windowEnd = day
+	} else if day.Before(v.windowStart()) {
+		// Scroll back so the cursor's week becomes the first column
+		v.windowEnd = day.AddDate(0, 0, heatmapWeeks*7-1)
+		if v.windowEnd.After(today) {
+			v.windowEnd = today
+		}
+	}
+}
+
+// windowStart returns the Monday starting the first week of the displayed window
+func (v *HeatmapView) windowStart() time.Time {
+	return startOfWeek(v.windowEnd).AddDate(0, 0, -7*(heatmapWeeks-1))
+}
+
+// startOfWeek returns the Monday of the week containing day
+func startOfWeek(day time.Time) time.Time {
+	offset := (int(day.Weekday()) + 6) % 7 // Monday = 0
+	return model.StartOfDay(day).AddDate(0, 0, -offset)
+}
+
+// Render renders the heatmap and the details of the selected day
+func (v *HeatmapView) Render() string {
+	start := v.windowStart()
+	days := int(v.windowEnd.Sub(start).Hours()/24+0.5) + 1
+	stats := model.ComputeStats(v.history, days, v.windowEnd)
+
+	// Index daily focus time by date
+	focus := make(map[time.Time]time.Duration, len(stats.Days))
+	var maxFocus time.Duration
+	for _, day := range stats.Days {
+		focus[day.Date] = day.FocusTime
+		if day.FocusTime > maxFocus {
+			maxFocus = day.FocusTime
+		}
+	}
+
+	cellWidth := 1
+	if v.width >= heatmapWeeks*2+6 {
+		cellWidth = 2
+	}
+
+	sections := []string{
+		v.renderGrid(start, focus, maxFocus, cellWidth),
+		v.renderLegend(),
+		"",
+		v.renderDayDetails(),
+	}
+
+	return lipgloss.NewStyle().
+		Padding(0, 2).
+		Width(v.width).
+		Render(strings.Join(sections, "\n"))
+}
+
+// renderGrid renders month labels and one row per weekday with a column per week
+func (v *HeatmapView) renderGrid(start time.Time, focus map[time.Time]time.Duration, maxFocus time.Duration, cellWidth int) string {
+	labelStyle := lipgloss.NewStyle().Foreground(ColorGrayText)
+	gutter := "    "
+
+	// Month labels over the first week that starts in each month
+	monthLine := []rune(strings.Repeat(" ", heatmapWeeks*cellWidth))
+	for week := 0; week < heatmapWeeks; week++ {
+		monday := start.AddDate(0, 0, week*7)
+		if week == 0 || monday.Day() <= 7 {
+			label := []rune(monday.Format("Jan"))
+			pos := week * cellWidth
+			if pos+len(label) <= len(monthLine) {
+				copy(monthLine[pos:], label)
+			}
+		}
+	}
+
+	rows := []string{labelStyle.Render(gutter + string(monthLine))}
+	dayNames := []string{"Mon", "", "Wed", "", "Fri", "", "Sun"}
+	today := model.StartOfDay(time.Now())
+
+	for weekday := 0; weekday < 7; weekday++ {
+		var line strings.Builder
+		line.WriteString(labelStyle.Render(fmt.Sprintf("%-4s", dayNames[weekday])))
+
+		for week := 0; week < heatmapWeeks; week++ {
+			day := start.AddDate(0, 0, week*7+weekday)
+			if day.After(v.windowEnd) || day.After(today) {
+				line.WriteString(strings.Repeat(" ", cellWidth))
+				continue
+			}
+
+			cell := "■" + strings.Repeat(" ", cellWidth-1)
+			style := lipgloss.NewStyle().Foreground(v.palette[heatmapLevel(focus[day], maxFocus)])
+			if day.Equal(v.cursor) {
+				// Highlight the selected day
+				style = style.Foreground(ColorStopButton).Bold(true)
+				cell = "◆" + strings.Repeat(" ", cellWidth-1)
+			}
+			line.WriteString(style.Render(cell))
+		}
+		rows = append(rows, line.String())
+	}
+
+	return strings.Join(rows, "\n")
+}
+
+// heatmapLevel maps focus time to a palette index relative to the busiest day
+func heatmapLevel(focus, maxFocus time.Duration) int {
+	if focus <= 0 || maxFocus <= 0 {
+		return 0
+	}
+	level := int(float64(focus)/float64(maxFocus)*heatmapLevels + 0.999)
+	if level < 1 {
+		level = 1
+	} else if level > heatmapLevels {
+		level = heatmapLevels
+	}
+	return level
+}
+
+// renderLegend renders the colour scale
+func (v *HeatmapView) renderLegend() string {
+	labelStyle := lipgloss.NewStyle().Foreground(ColorGrayText)
+
+	var legend strings.Builder
+	legend.WriteString(labelStyle.Render("    Less "))
+	for _, color := range v.palette {
+		legend.WriteString(lipgloss.NewStyle().Foreground(color).Render("■ "))
+	}
+	legend.WriteString(labelStyle.Render("More"))
+	return legend.String()
+}
+
+// renderDayDetails lists the sessions and tasks of the selected day
+func (v *HeatmapView) renderDayDetails() string {
+	sessions := v.history.SessionsBetween(v.cursor, v.cursor.AddDate(0, 0, 1))
+
+	var focusTime time.Duration
+	pomodoros := 0
+	tasks := make([]string, 0)
+	seenTasks := make(map[string]bool)
+	focusSessions := make([]model.Session, 0)
+
+	for _, session := range sessions {
+		if !session.IsFocus() {
+			continue
+		}
+		focusSessions = append(focusSessions, session)
+		focusTime += session.Duration
+		if session.Completed {
+			pomodoros++
+		}
+		if session.TaskDescription != "" && !seenTasks[session.TaskDescription] {
+			seenTasks[session.TaskDescription] = true
+			tasks = append(tasks, session.TaskDescription)
+		}
+	}
+
+	header := HideCompletedStyle.Render(v.cursor.Format("Monday, January 2, 2006")) +
+		lipgloss.NewStyle().Foreground(ColorGrayText).Render(
+			fmt.Sprintf("   %d 🍅  %s focus", pomodoros, model.FormatDuration(focusTime)))
+
+	lines := []string{header}
+	if len(focusSessions) == 0 {
+		lines = append(lines, lipgloss.NewStyle().Foreground(ColorGrayText).Render("No focus sessions on this day"))
+		return strings.Join(lines, "\n")
+	}
+
+	for i, session := range focusSessions {
+		if i == maxHeatmapSessions {
+			lines = append(lines, lipgloss.NewStyle().Foreground(ColorGrayText).Render(
+				fmt.Sprintf("… and %d more", len(focusSessions)-maxHeatmapSessions)))
+			break
+		}
+
+		description := session.TaskDescription
+		if description == "" {
+			description = "(no task)"
+		}
+		marker := "✓"
+		if !session.Completed {
+			marker = "✗"
+		}
+		lines = append(lines, fmt.Sprintf("%s %s–%s  %-7s %s",
+			TaskProgressStyle.Render(marker),
+			session.Start.Format("15:04"),
+			session.End.Format("15:04"),
+			model.FormatDuration(session.Duration),
+			description))
+	}
+
+	if len(tasks) > 0 {
+		lines = append(lines, "", TasksHeaderStyle.Render("Tasks: ")+strings.Join(tasks, ", "))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,151 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Styles used when rendering Markdown notes
+var (
+	markdownHeadingStyle = lipgloss.NewStyle().
+				Foreground(ColorTasksHeader).
+				Bold(true)
+
+	markdownBulletStyle = lipgloss.NewStyle().
+				Foreground(ColorTaskTag)
+
+	markdownQuoteStyle = lipgloss.NewStyle().
+				Foreground(ColorGrayText).
+				Italic(true)
+
+	markdownCodeStyle = lipgloss.NewStyle().
+				Foreground(ColorHideCompleted)
+
+	markdownBoldStyle = lipgloss.NewStyle().
+				Bold(true)
+
+	markdownItalicStyle = lipgloss.NewStyle().
+				Italic(true)
+)
+
+// RenderMarkdown renders a small subset of Markdown for the terminal:
+// headings, bullet and numbered lists, task checkboxes, blockquotes,
+// fenced code blocks and inline bold, italic and code spans
+func RenderMarkdown(source string) string {
+	var lines []string
+	inCodeBlock := false
+
+	for _, line := range strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		// Fenced code blocks are rendered verbatim
+		if strings.HasPrefix(trimmed, "```") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+		if inCodeBlock {
+			lines = append(lines, markdownCodeStyle.Render("  "+line))
+			continue
+		}
+
+		lines = append(lines, renderMarkdownLine(line))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// renderMarkdownLine renders a single line of Markdown outside of a code block
+func renderMarkdownLine(line string) string {
+	trimmed := strings.TrimLeft(line, " \t")
+	indent := strings.Repeat(" ", len(line)-len(trimmed))
+
+	switch {
+	case strings.HasPrefix(trimmed, "#"):
+		heading := strings.TrimSpace(strings.TrimLeft(trimmed, "#"))
+		return markdownHeadingStyle.Render(heading)
+
+	case strings.HasPrefix(trimmed, ">"):
+		quote := strings.TrimSpace(strings.TrimPrefix(trimmed, ">"))
+		return indent + markdownQuoteStyle.Render("│ "+quote)
+
+	case strings.HasPrefix(trimmed, "- [ ] "), strings.HasPrefix(trimmed, "* [ ] "):
+		return indent + markdownBulletStyle.Render("☐ ") + renderMarkdownInline(trimmed[6:])
+
+	case strings.HasPrefix(strings.ToLower(trimmed), "- [x] "), strings.HasPrefix(strings.ToLower(trimmed), "* [x] "):
+		return indent + markdownBulletStyle.Render("☑ ") +
+			lipgloss.NewStyle().Foreground(ColorGrayText).Render(renderMarkdownInline(trimmed[6:]))
+
+	case strings.HasPrefix(trimmed, "- "), strings.HasPrefix(trimmed, "* "), strings.HasPrefix(trimmed, "+ "):
+		return indent + markdownBulletStyle.Render("• ") + renderMarkdownInline(trimmed[2:])
+
+	case isNumberedListItem(trimmed):
+		dot := strings.Index(trimmed, ". ")
+		return indent + markdownBulletStyle.Render(trimmed[:dot+1]+" ") + renderMarkdownInline(trimmed[dot+2:])
+	}
+
+	return renderMarkdownInline(line)
+}
+
+// isNumberedListItem reports whether a line starts with "N. "
+func isNumberedListItem(line string) bool {
+	dot := strings.Index(line, ". ")
+	if dot <= 0 {
+		return false
+	}
+	for _, r := range line[:dot] {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// renderMarkdownInline renders inline code spans, bold and italic emphasis
+func renderMarkdownInline(text string) string {
+	var builder strings.Builder
+	// Emphasis markers only open at the start of a word, so snake_case stays intact
+	wordStart := true
+
+	for len(text) > 0 {
+		switch {
+		case strings.HasPrefix(text, "`"):
+			if end := strings.Index(text[1:], "`"); end >= 0 {
+				builder.WriteString(markdownCodeStyle.Render(text[1 : end+1]))
+				text = text[end+2:]
+				continue
+			}
+
+		case !wordStart:
+			// Inside a word: treat markers as plain text
+
+		case strings.HasPrefix(text, "**"), strings.HasPrefix(text, "__"):
+			marker := text[:2]
+			if end := strings.Index(text[2:], marker); end > 0 {
+				builder.WriteString(markdownBoldStyle.Render(text[2 : end+2]))
+				text = text[end+4:]
+				continue
+			}
+
+		case strings.HasPrefix(text, "*"), strings.HasPrefix(text, "_"):
+			marker := text[:1]
+			if end := strings.Index(text[1:], marker); end > 0 {
+				builder.WriteString(markdownItalicStyle.Render(text[1 : end+1]))
+				text = text[end+2:]
+				continue
+			}
+		}
+
+		// Plain text up to the next potential marker
+		next := strings.IndexAny(text[1:], "`*_")
+		if next < 0 {
+			builder.WriteString(text)
+			break
+		}
+		builder.WriteString(text[:next+1])
+		wordStart = strings.ContainsAny(text[next:next+1], " \t([{\"'")
+		text = text[next+1:]
+	}
+
+	return builder.String()
+}
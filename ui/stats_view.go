@@ -0,0 +1,197 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jackrudenko/pomodorocli/model"
+)
+
+const (
+	// Default and maximum number of weeks shown in the stats view
+	defaultStatsWeeks = 4
+	maxStatsWeeks     = 12
+	// Number of projects and tags listed in the breakdowns
+	maxBreakdownEntries = 5
+)
+
+// StatsView represents the productivity statistics component
+type StatsView struct {
+	history  *model.History
+	settings *model.Settings
+	width    int
+	weeks    int
+}
+
+// NewStatsView creates a new stats view
+func NewStatsView(history *model.History, settings *model.Settings, width int) *StatsView {
+	return &StatsView{
+		history:  history,
+		settings: settings,
+		width:    width,
+		weeks:    defaultStatsWeeks,
+	}
+}
+
+// SetWidth updates the width of the stats view
+func (v *StatsView) SetWidth(width int) {
+	v.width = width
+}
+
+// MoreWeeks extends the period shown by one week
+func (v *StatsView) MoreWeeks() {
+	if v.weeks < maxStatsWeeks {
+		v.weeks++
+	}
+}
+
+// FewerWeeks shortens the period shown by one week
+func (v *StatsView) FewerWeeks() {
+	if v.weeks > 1 {
+		v.weeks--
+	}
+}
+
+// Render renders the stats view
+func (v *StatsView) Render() string {
+	stats := model.ComputeStats(v.history, v.weeks*7, time.Now())
+
+	sectionStyle := TasksHeaderStyle.Bold(true)
+	sections := []string{
+		v.renderSummary(stats),
+		"",
+		sectionStyle.Render("Pomodoros per day"),
+		v.renderDailyChart(stats, func(day model.DayStats) float64 { return float64(day.Pomodoros) }, ColorStopButton),
+		"",
+		sectionStyle.Render("Focus minutes per day"),
+		v.renderDailyChart(stats, func(day model.DayStats) float64 { return day.FocusTime.Minutes() }, ColorTasksHeader),
+		"",
+		sectionStyle.Render("Weekly totals"),
+		v.renderWeeks(stats),
+		"",
+		lipgloss.JoinHorizontal(
+			lipgloss.Top,
+			v.renderBreakdown("By project", stats.ByProject),
+			"    ",
+			v.renderBreakdown("By tag", stats.ByTag),
+		),
+	}
+
+	return lipgloss.NewStyle().
+		Padding(0, 2).
+		Width(v.width).
+		Render(strings.Join(sections, "\n"))
+}
+
+// renderSummary renders the headline numbers for the period
+func (v *StatsView) renderSummary(stats model.Stats) string {
+	labelStyle := lipgloss.NewStyle().Foreground(ColorGrayText)
+	valueStyle := lipgloss.NewStyle().Foreground(ColorText).Bold(true)
+
+	compliance := "n/a"
+	if ratio, ok := stats.BreakCompliance(); ok {
+		compliance = fmt.Sprintf("%.0f%% (%d/%d)", ratio*100, stats.BreaksTaken, stats.BreaksRecorded)
+	}
+
+	period := fmt.Sprintf("Last %d week(s): %s – %s",
+		v.weeks,
+		stats.From.Format("Jan 2"),
+		stats.To.AddDate(0, 0, -1).Format("Jan 2"))
+
+	items := []string{
+		labelStyle.Render("Pomodoros ") + valueStyle.Render(fmt.Sprintf("%d", stats.Pomodoros)),
+		labelStyle.Render("Focus ") + valueStyle.Render(model.FormatDuration(stats.FocusTime)),
+		labelStyle.Render("Avg session ") + valueStyle.Render(model.FormatDuration(stats.AverageSession())),
+		labelStyle.Render("Break compliance ") + valueStyle.Render(compliance),
+	}
+	if stats.SkipAttempts > 0 {
+		items = append(items, labelStyle.Render("Skip attempts ")+valueStyle.Render(fmt.Sprintf("%d", stats.SkipAttempts)))
+	}
+
+	summary := HideCompletedStyle.Render(period) + "\n" + strings.Join(items, "   ")
+
+	// Daily goal and streak, unless the goal is disabled
+	streak := model.ComputeStreak(v.history, *v.settings, time.Now())
+	if streak.Goal > 0 {
+		goal := []string{
+			labelStyle.Render("Daily goal ") + valueStyle.Render(fmt.Sprintf("%d/%d today", streak.Today, streak.Goal)),
+			labelStyle.Render("Streak ") + valueStyle.Render(fmt.Sprintf("%d day(s)", streak.Current)),
+			labelStyle.Render("Best ") + valueStyle.Render(fmt.Sprintf("%d day(s)", streak.Longest)),
+		}
+		if len(v.settings.RestDays) > 0 {
+			goal = append(goal, labelStyle.Render("Rest days ")+valueStyle.Render(model.FormatWeekdays(v.settings.RestDays)))
+		}
+		summary += "\n" + strings.Join(goal, "   ")
+	}
+
+	return summary
+}
+
+// renderDailyChart renders one value per day as a column chart
+func (v *StatsView) renderDailyChart(stats model.Stats, value func(day model.DayStats) float64, color lipgloss.Color) string {
+	values := make([]float64, len(stats.Days))
+	labels := make([]string, len(stats.Days))
+	for i, day := range stats.Days {
+		values[i] = value(day)
+		// Label the start of each week with its date
+		if day.Date.Weekday() == time.Monday || i == 0 {
+			labels[i] = day.Date.Format("2")
+		}
+	}
+
+	colWidth := clamp((v.width-12)/len(values), 1, 3)
+	return renderColumnChart(values, labels, 5, colWidth, color)
+}
+
+// renderWeeks renders a horizontal bar per week
+func (v *StatsView) renderWeeks(stats model.Stats) string {
+	weeks := stats.Weeks()
+	maxMinutes := 0.0
+	for _, week := range weeks {
+		if minutes := week.FocusTime.Minutes(); minutes > maxMinutes {
+			maxMinutes = minutes
+		}
+	}
+
+	barWidth := clamp(v.width-40, 10, 50)
+	rows := make([]string, len(weeks))
+	for i, week := range weeks {
+		rows[i] = renderBarRow(
+			week.Date.Format("Jan 02"),
+			7,
+			week.FocusTime.Minutes(),
+			maxMinutes,
+			barWidth,
+			ColorTasksHeader,
+			fmt.Sprintf("%d 🍅  %s", week.Pomodoros, model.FormatDuration(week.FocusTime)))
+	}
+	return strings.Join(rows, "\n")
+}
+
+// renderBreakdown renders focus time per project or tag
+func (v *StatsView) renderBreakdown(title string, entries []model.BreakdownEntry) string {
+	rows := []string{TasksHeaderStyle.Bold(true).Render(title)}
+	if len(entries) == 0 {
+		rows = append(rows, lipgloss.NewStyle().Foreground(ColorGrayText).Render("No focus sessions yet"))
+		return strings.Join(rows, "\n")
+	}
+
+	maxMinutes := entries[0].FocusTime.Minutes()
+	barWidth := clamp((v.width-60)/2, 6, 20)
+	for i, entry := range entries {
+		if i == maxBreakdownEntries {
+			break
+		}
+		rows = append(rows, renderBarRow(
+			entry.Name,
+			14,
+			entry.FocusTime.Minutes(),
+			maxMinutes,
+			barWidth,
+			ColorTaskTag,
+			model.FormatDuration(entry.FocusTime)))
+	}
+	return strings.Join(rows, "\n")
+}
@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jackrudenko/pomodorocli/model"
+)
+
+// maxDetailLines limits how many lines of notes the detail pane shows
+const maxDetailLines = 10
+
+// maxDetailCommits limits how many of the task's latest commits the detail pane shows
+const maxDetailCommits = 5
+
+// TaskDetailView represents the detail pane showing the selected task's notes and commits
+type TaskDetailView struct {
+	width int
+}
+
+// NewTaskDetailView creates a new task detail view
+func NewTaskDetailView(width int) *TaskDetailView {
+	return &TaskDetailView{
+		width: width,
+	}
+}
+
+// SetWidth updates the width of the task detail view
+func (d *TaskDetailView) SetWidth(width int) {
+	d.width = width
+}
+
+// Render renders the notes of the given task as Markdown and the latest commits made while
+// working on it (newest first), or an empty string if it has neither
+func (d *TaskDetailView) Render(task model.Task, commits []model.Commit) string {
+	sections := make([]string, 0, 2)
+
+	if strings.TrimSpace(task.Notes) != "" {
+		header := lipgloss.JoinHorizontal(
+			lipgloss.Left,
+			TasksHeaderStyle.Render("Notes"),
+			"       ",
+			HideCompletedStyle.Render("[E] Edit notes"),
+		)
+
+		// Keep the pane compact so the task list stays visible
+		lines := strings.Split(RenderMarkdown(strings.TrimSpace(task.Notes)), "\n")
+		if len(lines) > maxDetailLines {
+			lines = append(lines[:maxDetailLines], lipgloss.NewStyle().Foreground(ColorGrayText).Render("…"))
+		}
+		sections = append(sections, lipgloss.JoinVertical(lipgloss.Left, header, strings.Join(lines, "\n")))
+	}
+
+	if len(commits) > 0 {
+		grayStyle := lipgloss.NewStyle().Foreground(ColorGrayText)
+		lines := []string{TasksHeaderStyle.Render("Commits")}
+		for i, commit := range commits {
+			if i == maxDetailCommits {
+				lines = append(lines, grayStyle.Render(fmt.Sprintf("… %d more", len(commits)-maxDetailCommits)))
+				break
+			}
+			lines = append(lines, grayStyle.Render(commit.ShortHash()+" "+commit.At.Local().Format("Jan 2 15:04"))+"  "+commit.Subject)
+		}
+		sections = append(sections, strings.Join(lines, "\n"))
+	}
+
+	if len(sections) == 0 {
+		return ""
+	}
+	return lipgloss.NewStyle().
+		Padding(1, 2, 0, 2).
+		Width(d.width).
+		Render(strings.Join(sections, "\n\n"))
+}
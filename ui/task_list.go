@@ -3,6 +3,7 @@ package ui
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/jackrudenko/pomodorocli/model"
@@ -37,27 +38,33 @@ func (t *TaskListView) SetWidth(width int) {
 
 // GetSelectedTask returns the currently selected task, or empty task if no tasks
 func (t *TaskListView) GetSelectedTask() model.Task {
-	tasks := t.taskManager.FilteredTasks()
-	if len(tasks) == 0 {
+	node, ok := t.GetSelectedNode()
+	if !ok {
 		return model.Task{} // Return an empty task
 	}
-	return tasks[t.selectedIndex%len(tasks)]
+	return node.Task
+}
+
+// GetSelectedNode returns the currently selected task with its position in the hierarchy
+func (t *TaskListView) GetSelectedNode() (model.TaskNode, bool) {
+	nodes := t.taskManager.VisibleTasks()
+	if len(nodes) == 0 {
+		return model.TaskNode{}, false
+	}
+	return nodes[t.selectedIndex%len(nodes)], true
 }
 
 // GetSelectedTaskPtr returns a pointer to the currently selected task, or nil if no tasks
 // This is needed for compatibility with code that expects pointers
 func (t *TaskListView) GetSelectedTaskPtr() *model.Task {
-	tasks := t.taskManager.FilteredTasks()
-	if len(tasks) == 0 {
+	node, ok := t.GetSelectedNode()
+	if !ok {
 		return nil
 	}
 
-	// Get the selected task
-	selectedTask := tasks[t.selectedIndex%len(tasks)]
-
 	// Find and return a pointer to the actual task in the task manager
 	for i := range t.taskManager.Tasks {
-		if t.taskManager.Tasks[i].ID == selectedTask.ID {
+		if t.taskManager.Tasks[i].ID == node.Task.ID {
 			return &t.taskManager.Tasks[i]
 		}
 	}
@@ -65,6 +72,11 @@ func (t *TaskListView) GetSelectedTaskPtr() *model.Task {
 	return nil
 }
 
+// ResetSelection moves the selection back to the first task, e.g. after the list is filtered
+func (t *TaskListView) ResetSelection() {
+	t.selectedIndex = 0
+}
+
 // MoveSelectionDown moves the selection down in the task list
 func (t *TaskListView) MoveSelectionDown() {
 	t.selectedIndex++
@@ -79,19 +91,32 @@ func (t *TaskListView) MoveSelectionUp() {
 
 // ToggleSelectedTaskComplete toggles the completion status of the selected task
 func (t *TaskListView) ToggleSelectedTaskComplete() {
-	tasks := t.taskManager.FilteredTasks()
-	if len(tasks) == 0 {
+	node, ok := t.GetSelectedNode()
+	if !ok {
 		return
 	}
 
-	// Get the index of the selected task
-	index := t.selectedIndex % len(tasks)
-	// Get the task
-	task := tasks[index]
-	// Toggle completion
-	task.ToggleComplete()
-	// Update the task in the task manager
-	t.taskManager.UpdateTask(task)
+	// Toggle completion through the task manager so recurring tasks spawn their next occurrence
+	t.taskManager.ToggleTaskComplete(node.Task.ID)
+}
+
+// ReestimateSelectedTask changes the planned pomodoros of the selected task by delta
+func (t *TaskListView) ReestimateSelectedTask(delta int) bool {
+	node, ok := t.GetSelectedNode()
+	if !ok {
+		return false
+	}
+	_, ok = t.taskManager.Reestimate(node.Task.ID, node.Task.PlannedPomodoros+delta, time.Now())
+	return ok
+}
+
+// ToggleSelectedCollapsed expands or collapses the subtasks of the selected task
+func (t *TaskListView) ToggleSelectedCollapsed() {
+	node, ok := t.GetSelectedNode()
+	if !ok || !node.HasChildren {
+		return
+	}
+	t.taskManager.ToggleCollapsed(node.Task.ID)
 }
 
 // ToggleShowCompleted toggles showing or hiding completed tasks
@@ -111,30 +136,43 @@ func (t *TaskListView) SetCurrentTask(task *model.Task) {
 	}
 }
 
-// DeleteSelectedTask deletes the currently selected task
-func (t *TaskListView) DeleteSelectedTask() {
-	tasks := t.taskManager.FilteredTasks()
-	if len(tasks) == 0 {
+// TrashSelectedTask moves the currently selected task together with its subtasks to the trash
+func (t *TaskListView) TrashSelectedTask() {
+	node, ok := t.GetSelectedNode()
+	if !ok {
 		return
 	}
 
-	// Get the index of the selected task
-	index := t.selectedIndex % len(tasks)
-	// Get the task
-	task := tasks[index]
-	// Delete the task from the task manager
-	t.taskManager.DeleteTask(task.ID)
+	t.taskManager.TrashTask(node.Task.ID, time.Now())
+	t.afterRemoval()
+}
 
+// ArchiveSelectedTask archives the currently selected task if it is completed
+func (t *TaskListView) ArchiveSelectedTask() bool {
+	node, ok := t.GetSelectedNode()
+	if !ok || !t.taskManager.ArchiveTask(node.Task.ID) {
+		return false
+	}
+
+	t.afterRemoval()
+	return true
+}
+
+// afterRemoval keeps the selection and current task valid after tasks leave the list
+func (t *TaskListView) afterRemoval() {
 	// Adjust selection index to prevent out of bounds
-	if len(t.taskManager.FilteredTasks()) > 0 && t.selectedIndex >= len(t.taskManager.FilteredTasks()) {
-		t.selectedIndex = len(t.taskManager.FilteredTasks()) - 1
+	visibleCount := len(t.taskManager.VisibleTasks())
+	if visibleCount > 0 && t.selectedIndex >= visibleCount {
+		t.selectedIndex = visibleCount - 1
 	}
 
-	// If the deleted task was the current task, clear it
-	if t.hasCurrentTask && t.currentTaskID == task.ID {
-		t.currentTask = nil
-		t.hasCurrentTask = false
-		t.currentTaskID = ""
+	// If the current task was removed (directly or as a subtask), clear it
+	if t.hasCurrentTask {
+		if task, found := t.taskManager.GetTask(t.currentTaskID); !found || !task.IsListed() {
+			t.currentTask = nil
+			t.hasCurrentTask = false
+			t.currentTaskID = ""
+		}
 	}
 }
 
@@ -162,9 +200,12 @@ func (t *TaskListView) Render() string {
 func (t *TaskListView) renderTaskList() string {
 	var tasks []string
 
+	nodes := t.taskManager.VisibleTasks()
+
 	// Add padding for tasks
-	for i, task := range t.taskManager.FilteredTasks() {
-		isSelected := i == (t.selectedIndex % len(t.taskManager.FilteredTasks()))
+	for i, node := range nodes {
+		task := node.Task
+		isSelected := i == (t.selectedIndex % len(nodes))
 		isCurrentTask := t.hasCurrentTask && task.ID == t.currentTaskID
 
 		// Task number and selection indicator
@@ -184,11 +225,11 @@ func (t *TaskListView) renderTaskList() string {
 		// Prepare task number for rendering, use digits for consistent width
 		taskNumber = fmt.Sprintf("%s%d", prefix, i+1)
 
-		// Task progress
-		taskProgress := task.PomodoroProgress()
+		// Task progress, rolled up from subtasks for parent tasks
+		taskProgress := node.Totals.PomodoroProgress()
 
-		// Task time spent
-		taskTimeSpent := task.FormattedTimeSpent()
+		// Task time spent, rolled up from subtasks for parent tasks
+		taskTimeSpent := node.Totals.FormattedTimeSpent()
 
 		// Check if the description contains "Link" to highlight it
 		description := task.Description
@@ -232,6 +273,11 @@ func (t *TaskListView) renderTaskList() string {
 			taskDescStyle = taskDescStyle.Bold(true)
 		}
 
+		// Overrun styling: more pomodoros spent than planned
+		if node.Totals.Pomodoros() > float64(node.Totals.PlannedPomodoros) {
+			taskProgressStyle = taskProgressStyle.Foreground(ColorStopButton)
+		}
+
 		// Completed task styling (lighter color)
 		if task.Completed {
 			taskDescStyle = taskDescStyle.Foreground(ColorProgressBar) // Use gray for completed tasks
@@ -266,11 +312,18 @@ func (t *TaskListView) renderTaskList() string {
 				strings.Repeat(" ", paddingSize)))
 		}
 
-		// Add +task prefix for the task description
-		renderedDesc := fmt.Sprintf("%s %s",
-			taskProgressStyle.Render("+task"),
+		// Add +task prefix for the task description, indented by depth
+		// with an expand/collapse marker for tasks that have subtasks
+		renderedDesc := fmt.Sprintf("%s%s %s",
+			strings.Repeat("  ", node.Depth),
+			taskProgressStyle.Render(treeMarker(node)),
 			taskDescStyle.Render(taskDescription))
 
+		// Show project, tags and due date after the description
+		if attributes := task.AttributesLabel(); attributes != "" {
+			renderedDesc += " " + lipgloss.NewStyle().Foreground(ColorGrayText).Render(attributes)
+		}
+
 		// Adjust the layout based on reference screenshot
 		// Based on the screenshot, we need specific ordering and spacing:
 		// 1. Number
@@ -312,8 +365,12 @@ func (t *TaskListView) renderTaskList() string {
 		tasks = append(tasks, fullTaskLine)
 	}
 
-	if len(t.taskManager.FilteredTasks()) == 0 {
-		tasks = append(tasks, TaskStyle.Render("No tasks. Add a new task with [N]."))
+	if len(nodes) == 0 {
+		if !t.taskManager.Filter.Empty() {
+			tasks = append(tasks, TaskStyle.Render("No tasks match the filter. Clear it with [0]."))
+		} else {
+			tasks = append(tasks, TaskStyle.Render("No tasks. Add a new task with [N]."))
+		}
 	}
 
 	// Add the "Add new task" control at the bottom with consistent styling
@@ -344,15 +401,37 @@ func (t *TaskListView) renderTaskControls() string {
 		MarginBottom(0).
 		Render(hideCompletedText)
 
-	// Add delete task control
+	// Add delete and archive task controls
 	deleteTask := HideCompletedStyle.
 		MarginTop(0).
 		MarginBottom(0).
-		Render("[D] Delete task")
+		Render("[D] Trash task")
+
+	archiveTask := HideCompletedStyle.
+		MarginTop(0).
+		MarginBottom(0).
+		Render("[X] Archive")
+
+	// Add subtask control
+	addSubtask := HideCompletedStyle.
+		MarginTop(0).
+		MarginBottom(0).
+		Render("[A] Add subtask")
 
 	// Simple spacer without explicit background
 	spacer := "       "
 
 	// Join horizontally without explicit background wrapping
-	return lipgloss.JoinHorizontal(lipgloss.Left, tasksHeader, spacer, hideCompleted, spacer, deleteTask)
+	return lipgloss.JoinHorizontal(lipgloss.Left, tasksHeader, spacer, hideCompleted, spacer, deleteTask, spacer, archiveTask, spacer, addSubtask)
+}
+
+// treeMarker returns the task prefix showing whether a task's subtasks are expanded
+func treeMarker(node model.TaskNode) string {
+	if !node.HasChildren {
+		return "+task"
+	}
+	if node.Task.Collapsed {
+		return "▸ +task"
+	}
+	return "▾ +task"
 }
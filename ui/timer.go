@@ -1,7 +1,9 @@
 package ui
 
 import (
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/jackrudenko/pomodorocli/model"
@@ -75,7 +77,11 @@ func (t *TimerView) Render() string {
 	}
 
 	// Compact rendering of components
-	components = append(components, currentTask, timer, progressBar, controls)
+	components = append(components, currentTask)
+	if t.showsBreathingGuide() {
+		components = append(components, renderBoxBreathing(t.timer.Duration-t.timer.Remaining))
+	}
+	components = append(components, timer, progressBar, controls)
 
 	// Join all components with center alignment and no extra padding
 	timerContent := lipgloss.NewStyle().
@@ -93,25 +99,38 @@ func (t *TimerView) renderCurrentTask() string {
 		breakType := "Short break"
 		if t.timer.Mode == model.LongBreakMode {
 			breakType = "Long break"
+		} else if t.timer.FlowtimeBreak > 0 {
+			breakType = "Flowtime break"
 		}
 
 		// Use a teal/blue color for breaks
 		breakStyle := CurrentTaskStyle.Copy().
 			Foreground(lipgloss.Color("#7BC0AB"))
 
+		// Suggest something restful to do, if any activities are configured
+		activity := "Take a rest"
+		if t.timer.BreakActivity != "" {
+			activity = t.timer.BreakActivity
+		}
+
 		return breakStyle.
 			PaddingBottom(1).
-			Render(breakType + " - Take a rest")
+			Render(breakType + " - " + activity)
 	}
 
 	// Standard task display for focus mode
 	if t.timer.CurrentTaskID != "" && t.timer.State == model.TimerRunning {
 		// Get the current task from the task manager
-		task, found := t.timer.TaskManager.GetTask(t.timer.CurrentTaskID)
-		if found {
+		// Show the full path so subtasks are displayed with their parents
+		path := t.timer.TaskManager.TaskPath(t.timer.CurrentTaskID)
+		if len(path) > 0 {
+			descriptions := make([]string, len(path))
+			for i, task := range path {
+				descriptions[i] = task.Description
+			}
 			return CurrentTaskStyle.
 				PaddingBottom(1).
-				Render(TaskProgressStyle.Render("+task ") + task.Description)
+				Render(TaskProgressStyle.Render("+task ") + strings.Join(descriptions, " › "))
 		}
 	}
 	return CurrentTaskStyle.
@@ -119,6 +138,12 @@ func (t *TimerView) renderCurrentTask() string {
 		Render("Select a task to start")
 }
 
+// showsBreathingGuide reports whether the box breathing guide is shown: during running short breaks, if enabled
+func (t *TimerView) showsBreathingGuide() bool {
+	return t.timer.Settings != nil && t.timer.Settings.BreathingGuide &&
+		t.timer.Mode == model.ShortBreakMode && t.timer.State == model.TimerRunning
+}
+
 // renderTimer renders the timer display using large ASCII characters
 func (t *TimerView) renderTimer() string {
 	timeStr := t.timer.FormatTime() // Format like "25:00"
@@ -203,8 +228,16 @@ func (t *TimerView) renderControls() string {
 		controls = StopButtonStyle.Background(nil).Render("Start [S]")
 	}
 
-	// Add Skip button during breaks
-	if t.timer.Mode == model.ShortBreakMode || t.timer.Mode == model.LongBreakMode {
+	// In strict mode the break has to be taken in full
+	if t.timer.BreakLocked() {
+		locked := "   Strict mode: break locked"
+		if t.timer.SkipAttempts > 0 {
+			locked += fmt.Sprintf(" (%d skip attempts)", t.timer.SkipAttempts)
+		}
+		controls = lipgloss.JoinHorizontal(lipgloss.Center, controls,
+			lipgloss.NewStyle().Foreground(ColorGrayText).Render(locked))
+	} else if t.timer.Mode == model.ShortBreakMode || t.timer.Mode == model.LongBreakMode {
+		// Add Skip button during breaks
 		skipStyle := StopButtonStyle.Copy().
 			Foreground(lipgloss.Color("#7BC0AB"))
 
@@ -212,5 +245,20 @@ func (t *TimerView) renderControls() string {
 		controls = lipgloss.JoinHorizontal(lipgloss.Center, controls, skipButton)
 	}
 
+	// Count down to a pomodoro starting automatically
+	if wait, ok := t.timer.AutoStartIn(); ok {
+		countdown := fmt.Sprintf("   Next pomodoro in %ds  [R] Cancel", int((wait+time.Second-1)/time.Second))
+		controls = lipgloss.JoinHorizontal(lipgloss.Center, controls,
+			lipgloss.NewStyle().Foreground(ColorTasksHeader).Render(countdown))
+	} else if t.timer.QueueEnded && t.timer.State == model.TimerStopped {
+		controls = lipgloss.JoinHorizontal(lipgloss.Center, controls,
+			lipgloss.NewStyle().Foreground(ColorGrayText).Render("   Task queue finished"))
+	}
+
+	// A Flowtime focus runs until a break is taken
+	if t.timer.IsFlowing() && t.timer.State != model.TimerStopped {
+		controls = lipgloss.JoinHorizontal(lipgloss.Center, controls, StopButtonStyle.Render("   Take a Break [B]"))
+	}
+
 	return controls
 }
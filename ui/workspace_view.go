@@ -0,0 +1,111 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jackrudenko/pomodorocli/storage"
+)
+
+// globalWorkspaceName labels the task list used outside of any workspace
+const globalWorkspaceName = "Global tasks"
+
+// WorkspaceView represents the switcher between the global task list and known workspaces
+type WorkspaceView struct {
+	width int
+	// The global task list (empty root) followed by the workspaces used before
+	entries []storage.KnownWorkspace
+	// Root of the workspace in use (empty for the global task list)
+	current       string
+	selectedIndex int
+	// Error reading the list of workspaces, shown instead of it
+	err error
+}
+
+// NewWorkspaceView creates a new workspace switcher
+func NewWorkspaceView(width int) *WorkspaceView {
+	return &WorkspaceView{
+		width: width,
+	}
+}
+
+// SetWidth updates the width of the workspace switcher
+func (v *WorkspaceView) SetWidth(width int) {
+	v.width = width
+}
+
+// Reload reads the list of known workspaces and selects the one in use
+func (v *WorkspaceView) Reload(workspacesPath, current string) {
+	v.current = current
+	v.entries = []storage.KnownWorkspace{{Name: globalWorkspaceName}}
+	v.err = nil
+	if workspacesPath != "" {
+		known, err := storage.LoadKnownWorkspaces(workspacesPath)
+		v.err = err
+		v.entries = append(v.entries, known...)
+	}
+
+	v.selectedIndex = 0
+	for i, entry := range v.entries {
+		if entry.Root == current {
+			v.selectedIndex = i
+		}
+	}
+}
+
+// MoveSelectionDown moves the selection down
+func (v *WorkspaceView) MoveSelectionDown() {
+	if v.selectedIndex < len(v.entries)-1 {
+		v.selectedIndex++
+	}
+}
+
+// MoveSelectionUp moves the selection up
+func (v *WorkspaceView) MoveSelectionUp() {
+	if v.selectedIndex > 0 {
+		v.selectedIndex--
+	}
+}
+
+// GetSelected returns the selected entry; its root is empty for the global task list
+func (v *WorkspaceView) GetSelected() (storage.KnownWorkspace, bool) {
+	if v.selectedIndex >= len(v.entries) {
+		return storage.KnownWorkspace{}, false
+	}
+	return v.entries[v.selectedIndex], true
+}
+
+// Render renders the workspace switcher
+func (v *WorkspaceView) Render() string {
+	grayStyle := lipgloss.NewStyle().Foreground(ColorGrayText)
+
+	lines := make([]string, 0, len(v.entries)+2)
+	for i, entry := range v.entries {
+		prefix := "   "
+		nameStyle := TaskStyle
+		if i == v.selectedIndex {
+			prefix = lipgloss.NewStyle().Foreground(ColorTaskTag).Bold(true).Render("👉 ")
+			nameStyle = nameStyle.Bold(true)
+		}
+
+		line := prefix + nameStyle.Render(entry.Name)
+		if entry.Root != "" {
+			line += "  " + grayStyle.Render(entry.Root)
+		}
+		if entry.Root == v.current {
+			line += "  " + lipgloss.NewStyle().Foreground(ColorTasksHeader).Render("(in use)")
+		}
+		lines = append(lines, line)
+	}
+
+	if v.err != nil {
+		lines = append(lines, "", grayStyle.Render("Could not read the workspace list: "+v.err.Error()))
+	} else if len(v.entries) == 1 {
+		lines = append(lines, "", grayStyle.Render("Workspaces appear here once used. Create one with a .pomodoro directory in a project."))
+	}
+
+	return lipgloss.NewStyle().
+		Padding(0, 2).
+		Width(v.width).
+		Render(strings.Join(lines, "\n"))
+}